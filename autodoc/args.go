@@ -2,8 +2,6 @@ package autodoc
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 )
 
@@ -13,6 +11,7 @@ import (
 //   * The package comment in autodoc.go
 //   * The Usage() function in autodoc.go
 //   * The autodoc tool documentation in docs/autodoc.md
+//   * The Options struct in options.go
 
 // Command line argument constants
 const (
@@ -31,8 +30,49 @@ const (
 	argTemplatesDir = "-templates-dir"
 	// File extension for template files
 	argTemplateExt = "-template-ext"
+	// Output profile - the layout of the generated documentation
+	argProfile = "-profile"
 	// Help flag - Show usage information
 	argHelp = "-help"
+	// Check flag - Compare the generated documentation to the files on disk
+	// instead of writing them
+	argCheck = "-check"
+	// Dry run flag - List the files that would be written and removed
+	// instead of writing them
+	argDryRun = "-dry-run"
+	// Verbose flag - Report the status of each generated file
+	argVerbose = "-verbose"
+	// Dump templates flag - Write the built-in templates to the templates
+	// directory instead of generating documentation
+	argDumpTemplates = "-dump-templates"
+	// Lint flag - Lint the schema documentation instead of writing it
+	argLint = "-lint"
+	// Format of the lint report
+	argLintFormat = "-lint-format"
+	// Comma separated list of lint rule severities, in the format RULE:SEVERITY
+	argLintRules = "-lint-rules"
+	// Path to write the JSON schema document of the provider to, instead of
+	// writing the documentation
+	argSchemaJSON = "-schema-json"
+	// Path to the JSON schema snapshot to generate the changelog from,
+	// instead of writing the documentation
+	argChangelogFrom = "-changelog-from"
+	// Path to the JSON schema snapshot to generate the changelog to
+	argChangelogTo = "-changelog-to"
+	// Format of the changelog
+	argChangelogFormat = "-changelog-format"
+	// Path to write the changelog to
+	argChangelogOut = "-changelog-out"
+	// Path to the schema baseline to check the compatibility of the provider
+	// against, instead of writing the documentation
+	argCompat = "-compat"
+	// Compat update flag - Write the schema baseline instead of checking it
+	argCompatUpdate = "-compat-update"
+	// Path to the allow file of intended breaking changes
+	argCompatAllow = "-compat-allow"
+	// Comma separated list of the Go package directories to generate the API
+	// reference for
+	argGoPackages = "-go-packages"
 )
 
 // Default values for command line arguments (if it is not explicitly set)
@@ -60,19 +100,9 @@ const (
 
 // Represents the parsed command line arguments
 type parsedArgs struct {
-	// The name of the provider being documented
-	providerName string
-	// The root output directory- mkdocs.yml will be placed here and the
-	// documentation directory will be placed as a subdirectory to this root.
-	rootDir string
-	// The name of the documentation directory. All autogenerated markdown files
-	// will be placed in this directory. The mkdocs.yml docs_dir will be set
-	// to this value. docs_dir will be placed under the specified root dir.
-	docsDir string
-	// The location to read & load template files
-	templatesDir string
-	// The file extension for template files
-	templateFileExt string
+	// The documentation options set by the arguments. Options that are not
+	// supplied are left unset; defaults are applied by DocumentWithOptions.
+	options Options
 	// Whether or not the user wants to display the usage dialog
 	help bool
 }
@@ -81,19 +111,15 @@ type parsedArgs struct {
 // Command Line Argument Utility Functions
 // -----------------------------------------------------------------------------
 
-// parseArgs retrieves the command line arguments supplied when invoking
-// the autodoc binary and parses them into a concrete implementation for use
-// in other functions. Returns the parsed command line arguments on success
-// or an error if encountered.
-func parseArgs() (parsedArgs, error) {
+// parseArgs parses the command line arguments supplied when invoking the
+// autodoc binary (excluding the name of the binary itself) into a concrete
+// implementation for use in other functions. Returns the parsed command line
+// arguments on success or an error if encountered.
+func parseArgs(rawArgs []string) (parsedArgs, error) {
 	args := parsedArgs{}
 
-	// Retrieve the supplied command line arguments
-	rawArgs := os.Args
-
-	// Iterate over each of the arguments. Index 0 contains the name/path of
-	// the binary executed, skip this param
-	for idx, val := range rawArgs[1:] {
+	// Iterate over each of the arguments
+	for idx, val := range rawArgs {
 
 		// Split on the first occurence of the argument assignment operator.
 		// Index 0 contains the flag name, index 1 contains the argument value
@@ -115,17 +141,60 @@ func parseArgs() (parsedArgs, error) {
 		// Determine the argument and set the value
 		switch argName {
 		case argProviderName:
-			args.providerName = argVal
+			args.options.ProviderName = argVal
 		case argRootDir:
-			args.rootDir = argVal
+			args.options.RootDir = argVal
 		case argDocsDir:
-			args.docsDir = argVal
+			args.options.DocsDir = argVal
 		case argTemplatesDir:
-			args.templatesDir = argVal
+			args.options.TemplatesDir = argVal
 		case argTemplateExt:
-			args.templateFileExt = argVal
+			args.options.TemplateExt = argVal
+		case argProfile:
+			args.options.Profile = argVal
 		case argHelp:
 			args.help = true
+		case argCheck:
+			args.options.Check = true
+		case argDryRun:
+			args.options.DryRun = true
+		case argVerbose:
+			args.options.Verbose = true
+		case argDumpTemplates:
+			args.options.DumpTemplates = true
+		case argLint:
+			args.options.Lint = true
+		case argLintFormat:
+			args.options.LintFormat = argVal
+		case argLintRules:
+			rules, rulesErr := parseLintRules(argVal)
+			if rulesErr != nil {
+				return args, fmt.Errorf(
+					"Malformatted argument at position [%d]: [%s]. %s",
+					idx,
+					val,
+					rulesErr.Error(),
+				)
+			}
+			args.options.LintRules = rules
+		case argSchemaJSON:
+			args.options.SchemaJSON = argVal
+		case argChangelogFrom:
+			args.options.ChangelogFrom = argVal
+		case argChangelogTo:
+			args.options.ChangelogTo = argVal
+		case argChangelogFormat:
+			args.options.ChangelogFormat = argVal
+		case argChangelogOut:
+			args.options.ChangelogOut = argVal
+		case argCompat:
+			args.options.Compat = argVal
+		case argCompatUpdate:
+			args.options.CompatUpdate = true
+		case argCompatAllow:
+			args.options.CompatAllow = argVal
+		case argGoPackages:
+			args.options.GoPackages = strings.Split(argVal, ",")
 		default:
 			return args, fmt.Errorf(
 				"Unrecognized argument at position [%d]: [%s]",
@@ -135,27 +204,22 @@ func parseArgs() (parsedArgs, error) {
 		} //end switch
 	} //end for
 
-	// check the values of the arguments. Set the default values if needed.
-	if args.providerName == "" {
-		args.providerName = defaultProviderName
-	}
-	if args.rootDir == "" {
-		// Get the current working directory- used in default values
-		cwd, cwdErr := os.Getwd()
-		if cwdErr != nil {
-			return args, cwdErr
+	return args, nil
+}
+
+// parseLintRules parses a comma separated list of lint rule severities in the
+// format RULE:SEVERITY (ie: "empty-description:off,unknown-tag:warning").
+func parseLintRules(value string) (map[string]LintSeverity, error) {
+	rules := map[string]LintSeverity{}
+	for _, rule := range strings.Split(value, ",") {
+		parts := strings.SplitN(rule, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf(
+				"Expected RULE:SEVERITY, got [%s].",
+				rule,
+			)
 		}
-		args.rootDir = cwd
-	}
-	if args.docsDir == "" {
-		args.docsDir = filepath.Join(args.rootDir, defaultDocsDir)
+		rules[parts[0]] = LintSeverity(parts[1])
 	}
-	if args.templatesDir == "" {
-		args.templatesDir = filepath.Join(args.rootDir, defaultTemplatesDir)
-	}
-	if args.templateFileExt == "" {
-		args.templateFileExt = defaultTemplateFileExt
-	}
-
-	return args, nil
+	return rules, nil
 }
@@ -18,35 +18,276 @@
 //   -templates-dir
 //     The directory to search for template files. Templates are searched
 //     and loaded recursively from this directory. Defaults to
-//     '$(cwd)/templates'
+//     '$(cwd)/templates'. The directory is optional; see Built-in Templates
+//     below.
 //   -template-ext
 //     File extension for template files. Defaults to '.template'
+//   -profile=PROFILE
+//     Output profile, which selects the site generator that lays out the
+//     generated files. One of 'mkdocs', 'registry', 'hugo', 'docusaurus', or
+//     'bare'. Defaults to 'mkdocs'. See Output Profiles below.
+//   -check
+//     Check that the documentation on disk is up to date instead of writing
+//     it. Every file is generated in memory and compared to the existing
+//     file. A unified diff is printed for every file that is out of date,
+//     along with the files that would be created or removed. Nothing is
+//     written. autodoc exits 1 if any file is out of date.
+//   -dry-run
+//     List the files that would be created, updated, or removed instead of
+//     writing them. Nothing is written.
+//   -verbose
+//     Report whether each generated file was created, updated, unchanged,
+//     or removed.
+//   -dump-templates
+//     Write the built-in templates to the templates directory instead of
+//     generating documentation, as a starting point for customization.
+//     Existing templates are not over-written.
+//   -lint
+//     Lint the schema documentation instead of writing it. autodoc exits 1 if
+//     any error severity findings are reported. See Linting below.
+//   -lint-format=FORMAT
+//     Format of the lint report, either 'text' or 'json'. Defaults to 'text'.
+//   -lint-rules=RULE:SEVERITY[,RULE:SEVERITY...]
+//     Over-rides the severity of lint rules. Severity is one of 'error',
+//     'warning', or 'off'.
+//   -schema-json=PATH
+//     Write the schema of the provider, its resources, and its data sources
+//     to PATH as a JSON document instead of generating documentation. '-'
+//     writes it to stdout. See JSON Schema below.
+//   -changelog-from=PATH
+//     Write a changelog of the schema changes since the JSON schema snapshot
+//     at PATH instead of generating documentation. See Schema Changelog
+//     below.
+//   -changelog-to=PATH
+//     JSON schema snapshot to compare to. Defaults to the schema of the
+//     provider being documented.
+//   -changelog-format=FORMAT
+//     Format of the changelog, either 'markdown' or 'json'. Defaults to
+//     'markdown'.
+//   -changelog-out=PATH
+//     Path to write the changelog to. Defaults to '-', which writes it to
+//     stdout.
+//   -compat=PATH
+//     Check the schema of the provider for breaking changes since the schema
+//     baseline at PATH instead of generating documentation. autodoc exits 1
+//     if there are breaking changes that are not in the allow file. See
+//     Compatibility Check below.
+//   -compat-update
+//     Write the schema baseline to the -compat path instead of checking it.
+//   -compat-allow=PATH
+//     Allow file listing the intended breaking changes, each with a
+//     justification.
+//   -go-packages=DIR[,DIR...]
+//     Directories of the Go packages to generate the API reference for. A
+//     directory ending in '/...' also selects every package under it. See
+//     Go API Reference below.
 //
 // Arguments can be assigned values by using the '=' operator:
 //   $> autodoc -root='/my/path'
 //
+// Document reads these arguments from os.Args. To run autodoc from other
+// tools (ie: go generate wrappers or tests) without faking the process
+// arguments, call DocumentWithOptions with an Options value instead. The
+// Options fields mirror the arguments above and can also direct output to
+// another FileSystem, such as an in-memory one.
+//
 // This application will exit 1 on error, 0 on success.
 //
 // The following files are generated as output by the application. Let
 // $(cwd) be the value supplied to -root, and $(docs) be the value supplied
 // to -docs-dir:
 //   1. $(cwd)/mkdocs.yml
-//     mkdocs configuration file
+//     mkdocs configuration file. If it already exists, only its site_name,
+//     docs_dir, and nav keys are generated; all other keys (ie: theme,
+//     plugins, extra_css, markdown_extensions) are kept as they are, in
+//     their order and with their comments.
 //   2. $(cwd)/$(docs)/index.md
 //     provider documentation file
 //   3. $(cwd)/$(docs)/godoc.md
-//     Godoc documentation file
-//   4. $(cwd)/$(docs)/resources/*.md
+//     Index of the Go API reference
+//   4. $(cwd)/$(docs)/godoc/IMPORT_PATH.md
+//     Go API reference of each Go package listed with -go-packages
+//   5. $(cwd)/$(docs)/resources/*.md
 //     All resource documentation. There will be one md file for each resource.
 //     The resource files will be named corresponding to its name in the
 //     provider's ResourcesMap.
-//   5. $(cwd)/$(docs)/datasources/*.md
+//   6. $(cwd)/$(docs)/datasources/*.md
 //     All datasource documentation. There will be one md file for each
 //     datasource.  The datasource files will be named corresponding to its
 //     name in the provider's DataSourcesMap.
 //
+// Output Profiles
+//
+// The 'mkdocs' profile generates the files listed above from the templates
+// directory. The 'registry' profile generates the layout expected by the
+// Terraform Registry from built-in templates, so no templates directory is
+// needed:
+//   1. $(cwd)/$(docs)/index.md
+//     provider documentation
+//   2. $(cwd)/$(docs)/resources/*.md
+//     resource documentation, named without the provider prefix (ie: the
+//     resource foo_bar is documented in bar.md)
+//   3. $(cwd)/$(docs)/data-sources/*.md
+//     data source documentation, named without the provider prefix
+// Each registry page starts with YAML front matter (page_title, subcategory
+// from the @CATEGORY tag, description) and has the standard Example Usage,
+// Argument Reference, Attributes Reference, and Import sections. The built-in
+// templates are named registry-index.md.template and
+// registry-schema.md.template and can be over-ridden by user templates of the
+// same name. Front matter is exposed to templates as {{.FrontMatter}}.
+//
+// The remaining profiles render the pages from the templates directory, in
+// the same layout as the 'mkdocs' profile, for other site generators:
+//   'hugo'
+//     $(cwd)/hugo.toml with the main menu, with $(docs) as the content
+//     directory. The provider page is $(cwd)/$(docs)/_index.md. Pages start
+//     with YAML front matter (title, description).
+//   'docusaurus'
+//     $(cwd)/sidebars.js with the sidebar. Pages start with YAML front matter
+//     (title, sidebar_label, description), and the provider page is served
+//     at the root of the docs (slug).
+//   'bare'
+//     Plain Markdown without site configuration or front matter, to be
+//     browsed as it is. The provider page is $(cwd)/$(docs)/README.md, from
+//     the built-in bare-index.md.template, which renders index.md.template
+//     followed by links to every resource and data source page.
+// The site configuration files are rendered from the built-in
+// hugo.toml.template and sidebars.js.template, which can be over-ridden. Page
+// templates should start with {{.FrontMatter}} and format call-outs with the
+// admonition template function, so they render in every profile.
+//
+// Guides
+//
+// Each NAME.md.template in the guides sub directory of the templates
+// directory is a hand-written guide (ie: authentication, upgrade notes),
+// rendered to $(cwd)/$(docs)/guides/NAME.md. A guide template may start with
+// YAML front matter with a title (defaults to NAME), a description, and a
+// weight:
+//   ---
+//   title: Authentication
+//   weight: 10
+//   ---
+// The front matter is not part of the output; pages get the front matter of
+// the profile as {{.FrontMatter}}. Guides are added to the navigation of
+// every profile, ordered by weight and then by title. Guide templates are
+// given the provider model: its arguments and blocks, the schema of each
+// resource and data source keyed by name (ie:
+// {{(index .Resources "foo_bar").Arguments}}), and the pages of the
+// resources, data sources, and guides.
+//
+// Go API Reference
+//
+// The Go packages listed with -go-packages are parsed from their source files
+// with go/parser and go/doc, without a godoc server. Each package is rendered
+// with godoc-package.md.template to $(cwd)/$(docs)/godoc/IMPORT_PATH.md, where
+// the import path follows the module path of the nearest go.mod, with its
+// documentation and its exported constants, variables, functions, and types.
+// The pages are added to the navigation of every profile and listed in
+// godoc.md. Test files and files excluded by build constraints are skipped.
+//
+// Examples
+//
+// Templates are given a complete HCL configuration block for the provider,
+// resource, or data source as {{.ExampleHCL}}. It is generated from the
+// @EXAMPLE values of the arguments, and includes nested blocks that are
+// required or have examples. Values of string arguments are quoted (ie:
+// "@EXAMPLE my foo" => "my foo"); values of other arguments are quoted
+// unless they are literals (ie: 10, true, ["a"]). A value prefixed with "="
+// is used verbatim as an HCL expression (ie: "@EXAMPLE =var.region").
+// Required arguments without an example are set to a placeholder of their
+// type. The block is checked with the HCL parser; a malformed "=" expression
+// fails the generation.
+//
+// JSON Schema
+//
+// The -schema-json mode writes a single JSON document with the shape of the
+// output of `terraform providers schema -json`: the provider, every resource,
+// and every data source under "provider_schemas", with their attributes,
+// nested "block_types", and cty types. The metadata autodoc parses that
+// Terraform does not know about (summaries, examples, defaults, ForceNew,
+// constraints, import IDs, timeouts) is added under "autodoc" keys on the
+// resources, attributes, and nested blocks. Keys are sorted, so the document
+// is stable and can be committed as a schema snapshot.
+//
+// Schema Changelog
+//
+// The -changelog-from mode compares two JSON schema snapshots, or one
+// snapshot and the provider being documented, and reports the resources and
+// data sources that were added or removed, and the attributes and nested
+// blocks that were added, removed, renamed (removed and added with the same
+// type in the same block), changed type, made required, made ForceNew,
+// deprecated, or given a different default. The Markdown changelog is
+// rendered from the built-in changelog.md template, which can be over-ridden
+// like any other template, so it can be written straight into the docs
+// directory as a changelog page. A $(docs)/changelog.md page is added to the
+// mkdocs navigation.
+//
+// Compatibility Check
+//
+// The -compat mode captures the schema of the provider into a baseline file
+// (with -compat-update) that is committed with the provider, and later
+// compares the provider to it. Each change is classified as breaking or
+// non-breaking. Removed resources, data sources, and attributes (including
+// renames), changed types, arguments or blocks made required, new required
+// arguments (unless they are nested under a new or optional block), new
+// ForceNew attributes, and lowered MaxItems are breaking.
+// Breaking changes fail the check unless they are listed in the allow file,
+// a JSON document of the form:
+//   {"allow": [{"kind": "type-changed", "object_type": "resource",
+//     "object": "foo_bar", "attribute": "size",
+//     "justification": "Documented in the v2 upgrade guide."}]}
+// The kind, object type, object, and attribute must match the change as it
+// is reported in the JSON changelog. Every entry needs a justification.
+//
+// Custom Tags
+//
+// Providers can define their own metadata tags (ie: @SINCE 2.3.0) with
+// RegisterTag before calling Document. Custom tags are stripped from the
+// descriptions like the built-in tags, and their values are available to
+// templates in the Custom map of .Meta, each argument, and each attribute.
+//
+// Linting
+//
+// The -lint mode walks the same schema as the documentation and reports the
+// following rules. Each rule has an ID and a default severity:
+//   empty-description (warning)
+//     An attribute has no description once metadata tags are removed.
+//   example-on-computed (warning)
+//     @EXAMPLE is set on a Computed-only attribute.
+//   meta-not-computed (error)
+//     The __meta__ attribute is not Computed.
+//   unknown-tag (error)
+//     A description contains an unrecognized metadata tag (ie: @SUMARY).
+//   missing-summary (warning)
+//     A resource or data source has no Description or @SUMMARY.
+//   unknown-conflicts-with (error)
+//     ConflictsWith names an attribute that does not exist.
+//   invalid-tag (error)
+//     The metadata tags of a description cannot be parsed (ie: an
+//     unterminated quoted value).
+//   invalid-ref (error)
+//     A @REF reference names a resource, data source, or attribute that
+//     does not exist.
+//   repeated-tag (warning)
+//     A tag that accepts a single value is repeated. Only its first value
+//     is used.
+//
+// Rules are suppressed for a single attribute with the @NOLINT tag in its
+// description, followed by a comma separated list of rule IDs (or no value
+// to suppress all rules). Resource-level rules are suppressed in the
+// resource's Description or in the description of the __meta__ attribute.
+//
 // This application assumes the user has read/write access to all output paths
 //
+// Output directories are created as needed. Each file is rendered in memory
+// and written to a temporary file that is renamed over the existing file, and
+// nothing is written if any file fails to render. The files written by a run
+// are listed in $(cwd)/.autodoc-manifest. The next run removes the files in
+// the manifest that it no longer generates (ie: the page of a deleted
+// resource). Files that are not in the manifest are never removed; -dry-run
+// and -check warn about the pages in the resource and data source
+// directories that are not generated and not in the manifest.
+//
 // This application uses the following template associations for each output
 // file:
 //   mkdocs.yml.template
@@ -54,15 +295,83 @@
 //   index.md.template
 //     $(cwd)/$(docs)/index.md => Provider documentation
 //   godoc.md.template
-//     $(cwd)/$(docs)/godoc.md => Container page for godoc
+//     $(cwd)/$(docs)/godoc.md => Index of the Go API reference
+//   godoc-package.md.template
+//     $(cwd)/$(docs)/godoc/IMPORT_PATH.md => Go API reference of a package
 //   resource.md.template
 //     $(cwd)/$(docs)/resources/*.md => Documentation for all resources
 //   datasource.md.template
 //     $(cwd)/$(docs)/datasources/*.md => Documentation for all data sources
+//   resources/NAME.md.template, datasources/NAME.md.template
+//     Optional. Documentation for the resource or data source NAME, in place
+//     of resource.md.template or datasource.md.template (ie:
+//     resources/foo_bar.md.template). Only these two sub directories of the
+//     templates directory are matched by name.
+//
+// Built-in Templates
+//
+// autodoc carries a default template set compiled into the package. A default
+// template is used whenever the templates directory does not contain a
+// template of the same name, so user templates over-ride the defaults one
+// file at a time and the templates directory is optional. The defaults can be
+// written out with -dump-templates. All of the templates above have defaults.
+//
+// autodoc also defines the following built-in partial templates. They can be
+// included from any of the templates above and are over-ridden by a user
+// template of the same name:
+//   _argument.template
+//     Renders a single argument as a list item: its flags, type, and
+//     description followed by its default, item counts, and constraints
+//     (ConflictsWith, ExactlyOneOf, AtLeastOneOf, RequiredWith, ComputedWhen)
+//     and deprecation. It also defines "autodoc.constraints", which renders
+//     only the latter. Include with
+//     {{range .Arguments}}{{template "_argument.template" .}}{{end}}
+//   _blocks.template
+//     Renders a section for each nested block of a provider, resource, or
+//     data source, walking nested blocks to arbitrary depth. Each section is
+//     preceded by an anchor that the nested block's argument and attribute
+//     types link to. Include with {{template "_blocks.template" .}}
+//   _arguments.template
+//     Renders the argument list of a provider, resource, or data source with
+//     _argument.template, or a sentence saying it has no arguments. Include
+//     with {{template "_arguments.template" .}}
+//   _example.template
+//     Renders .ExampleHCL as a fenced hcl code block. Include with
+//     {{template "_example.template" .}}
+// Templates whose names start with '_' are partials. User partials can be
+// placed anywhere under the templates directory (ie: in a _partials sub
+// directory). A template that includes a template that does not exist fails
+// the generation with an error naming both, before any file is rendered.
+//
+// Template Functions
+//
+// All templates can use the following functions in addition to the
+// text/template built-ins:
+//   escapeMarkdown, escapeHTML, tableCell
+//     Escape text for Markdown, HTML, or a Markdown table cell.
+//   slug, blockAnchor
+//     The anchor of a heading, or of the section of a nested block.
+//   code, codeFence LANG, indent N, wrap WIDTH
+//     Format text as a code span or fenced code block, indent it, or
+//     word-wrap it.
+//   plural N SINGULAR PLURAL, join SEP, sort
+//     Pluralize a word, and join or sort a list of strings.
+//   hcl
+//     Format a value as an HCL literal.
+//   admonition KIND TEXT
+//     Format text as a "note" or "warning" call-out in the Markdown dialect
+//     of the output profile.
+//   resourcePath $ NAME, resourceLink $ NAME, dataSourcePath $ NAME,
+//   dataSourceLink $ NAME
+//     The path of, or a Markdown link to, a resource or data source page,
+//     relative to the page being rendered.
+// Programs calling DocumentWithOptions can add their own functions, or
+// over-ride the built-in ones, with Options.Funcs.
 package autodoc
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -76,15 +385,16 @@ const (
 	ExitError = 1
 )
 
-// Document is the entry point into autodoc execution. The command
-// line arguments and templates are read and parsed. The provider reference
-// is parsed to generate the documentation. This function will return a list
-// of errors.  If this list is empty, no errors were encountered.
+// Document is the command line entry point into autodoc execution. The
+// command line arguments are read and parsed into Options, and the provider
+// reference is documented with DocumentWithOptions. This function will return
+// a list of errors.  If this list is empty, no errors were encountered.
 func Document(provider *schema.Provider) []error {
 	errors := []error{}
 
-	// Parse command line arguments into concrete struct representation
-	args, argsErr := parseArgs()
+	// Parse command line arguments into concrete struct representation. Index
+	// 0 contains the name/path of the binary executed, skip this param
+	args, argsErr := parseArgs(os.Args[1:])
 	if argsErr != nil {
 		errors = append(errors, argsErr)
 		return errors
@@ -95,72 +405,180 @@ func Document(provider *schema.Provider) []error {
 		return errors
 	}
 
-	// Using the parsed arguments, recursively load all the templates from
-	// the specified directory
-	templates, tmplErr := parseTemplates(args)
+	return DocumentWithOptions(provider, args.options)
+}
+
+// DocumentWithOptions is the library entry point into autodoc execution. The
+// templates are read and parsed from the options' templates directory and
+// the provider reference is parsed to generate the documentation. This
+// function will return a list of errors.  If this list is empty, no errors
+// were encountered.
+func DocumentWithOptions(provider *schema.Provider, options Options) []error {
+	errors := []error{}
+
+	// provider reference should not be nil
+	if provider == nil {
+		errors = append(errors, fmt.Errorf("Provider reference is nil."))
+		return errors
+	}
+
+	opts, optsErr := options.withDefaults()
+	if optsErr != nil {
+		errors = append(errors, optsErr)
+		return errors
+	}
+
+	// Write out the built-in templates for customization instead of
+	// generating documentation
+	if opts.DumpTemplates {
+		return dumpTemplates(opts)
+	}
+
+	// In lint mode, report problems with the schema documentation instead
+	// of generating it
+	if opts.Lint {
+		return lintProvider(provider, opts)
+	}
+
+	// Export the provider schema as JSON instead of generating documentation
+	if opts.SchemaJSON != "" {
+		return exportSchemaJSON(provider, opts)
+	}
+
+	// Compare schema snapshots instead of generating documentation
+	if opts.ChangelogFrom != "" {
+		return writeChangelog(provider, opts)
+	}
+
+	// Check the schema against the committed baseline for breaking changes
+	// instead of generating documentation
+	if opts.Compat != "" {
+		return checkCompat(provider, opts)
+	}
+
+	// Look up the site generator of the output profile. It determines the
+	// documentation layout.
+	site, siteErr := lookupSiteGenerator(opts.Profile)
+	if siteErr != nil {
+		errors = append(errors, siteErr)
+		return errors
+	}
+
+	// Using the options, recursively load all the templates from the
+	// specified directory
+	templates, tmplErr := parseTemplates(opts)
 	if tmplErr != nil {
 		errors = append(errors, tmplErr)
 		return errors
 	}
 
-	// Creates a bidirectional error channel. This is for communication
+	// Load the hand-written guides, ordered by the weight in their front
+	// matter
+	guides, guidesErr := loadGuides(opts)
+	if guidesErr != nil {
+		errors = append(errors, guidesErr)
+		return errors
+	}
+
+	// Parse the Go packages of the API reference from their source files
+	packages, packagesErr := loadGoPackages(opts)
+	if packagesErr != nil {
+		errors = append(errors, packagesErr)
+		return errors
+	}
+
+	// Creates a bidirectional output channel. This is for communication
 	// across the goroutines. As goroutines are spun up to generate the
-	// documentation, they communicate their error status back through this
-	// channel
-	errChan := make(chan error, 1)
+	// documentation, they communicate their rendered output and error status
+	// back through this channel
+	outChan := make(chan renderedFile, 1)
 
 	// Total number of go routines. This signals how many outputs to receive
-	// on the error channel before exiting.
+	// on the output channel before exiting.
 	totalGoroutines := 0
 
-	// generate mkdocs.yml file
-	totalGoroutines += 1
-	go generateMkdocsYml(
-		mkdocsYmlDoc{
-			goroutineBase: goroutineBase{
-				outFile: filepath.Join(
-					args.rootDir,
-					"mkdocs.yml",
-				),
-				template:     templates,
-				templateName: mkdocsYmlTemplate + args.templateFileExt,
-				errChan:      errChan,
+	// generate the site configuration files (ie: mkdocs.yml)
+	data := newSiteData(provider, opts, site)
+	data.Guides = newGuidePages(site, guides)
+	data.GoPackages = newGoPackagePages(site, packages)
+	// the changelog is written by the -changelog-from mode, not generated
+	if _, readErr := opts.FileSystem.ReadFile(filepath.Join(opts.DocsDir, changelogPage)); readErr == nil {
+		data.Changelog = changelogPage
+	}
+	references := providerReferences(provider)
+	for _, file := range site.siteFiles(opts) {
+		totalGoroutines += 1
+		go generateSiteFile(
+			siteFileDoc{
+				goroutineBase: goroutineBase{
+					outFile:      file.path,
+					template:     templates,
+					templateName: file.template + opts.TemplateExt,
+					outChan:      outChan,
+				},
+				data:       data,
+				merge:      file.merge,
+				fileSystem: opts.FileSystem,
 			},
-			provider: provider,
-			args:     args,
-		},
-	)
+		)
+	}
 
-	// generate godoc.md file
-	totalGoroutines += 1
-	go generateGodocMd(
-		goroutineBase{
-			outFile: filepath.Join(
-				args.docsDir,
-				"godoc.md",
-			),
-			template:     templates,
-			templateName: godocMdTemplate + args.templateFileExt,
-			errChan:      errChan,
-		},
-	)
+	// generate a page for each guide
+	for _, g := range guides {
+		totalGoroutines += 1
+		go generateGuideDoc(
+			guideDoc{
+				goroutineBase: goroutineBase{
+					outFile:      sitePagePath(opts, site, typeGuide, g.name),
+					template:     templates,
+					templateName: g.template,
+					outChan:      outChan,
+				},
+				data: newGuideData(provider, opts, site, data, g),
+			},
+		)
+	}
 
-	// generate index.md for provider documentation
+	// generate the API reference page of each Go package
+	for _, pkg := range packages {
+		totalGoroutines += 1
+		go generateGoPackageDoc(
+			goPackageDoc{
+				goroutineBase: goroutineBase{
+					outFile:      sitePagePath(opts, site, typeGoPackage, pkg.importPath),
+					template:     templates,
+					templateName: goPackageMdTemplate + opts.TemplateExt,
+					outChan:      outChan,
+				},
+				data: newGoPackageData(opts, site, data, pkg),
+			},
+		)
+	}
+
+	// generate the provider documentation
 	totalGoroutines += 1
 	go generateSchemaDoc(
 		schemaDoc{
 			goroutineBase: goroutineBase{
-				outFile: filepath.Join(
-					args.docsDir,
-					"index.md",
-				),
+				outFile:      sitePagePath(opts, site, typeProvider, opts.ProviderName),
 				template:     templates,
-				templateName: providerMdTemplate + args.templateFileExt,
-				errChan:      errChan,
+				templateName: site.pageTemplate(typeProvider) + opts.TemplateExt,
+				outChan:      outChan,
 			},
-			schemaType: typeProvider,
-			name:       args.providerName,
-			schema:     provider.Schema,
+			schemaType:   typeProvider,
+			name:         opts.ProviderName,
+			schema:       provider.Schema,
+			providerName: opts.ProviderName,
+			providerType: providerType(provider),
+			resources:    data.Resources,
+			dataSources:  data.DataSources,
+			categories:   data.Categories,
+			categorized:  data.Categorized,
+			guides:       data.Guides,
+			goPackages:   data.GoPackages,
+			frontMatter:  site.frontMatter,
+			provider:     provider,
+			site:         site,
 		},
 	)
 
@@ -170,18 +588,21 @@ func Document(provider *schema.Provider) []error {
 		go generateSchemaDoc(
 			schemaDoc{
 				goroutineBase: goroutineBase{
-					outFile: filepath.Join(
-						args.docsDir,
-						"resources",
-						name+".md",
-					),
+					outFile:      sitePagePath(opts, site, typeResource, name),
 					template:     templates,
-					templateName: resourceMdTemplate + args.templateFileExt,
-					errChan:      errChan,
+					templateName: schemaTemplateName(templates, opts, site, typeResource, name),
+					outChan:      outChan,
 				},
-				schemaType: typeResource,
-				name:       name,
-				schema:     resource.Schema,
+				schemaType:   typeResource,
+				name:         name,
+				schema:       resource.Schema,
+				resource:     resource,
+				providerName: opts.ProviderName,
+				providerType: providerType(provider),
+				frontMatter:  site.frontMatter,
+				provider:     provider,
+				site:         site,
+				referencedBy: references[schemaObject{schemaType: typeResource, name: name}],
 			},
 		)
 	}
@@ -192,30 +613,50 @@ func Document(provider *schema.Provider) []error {
 		go generateSchemaDoc(
 			schemaDoc{
 				goroutineBase: goroutineBase{
-					outFile: filepath.Join(
-						args.docsDir,
-						"datasources",
-						name+".md",
-					),
+					outFile:      sitePagePath(opts, site, typeDataSource, name),
 					template:     templates,
-					templateName: dataSourceMdTemplate + args.templateFileExt,
-					errChan:      errChan,
+					templateName: schemaTemplateName(templates, opts, site, typeDataSource, name),
+					outChan:      outChan,
 				},
-				schemaType: typeDataSource,
-				name:       name,
-				schema:     resource.Schema,
+				schemaType:   typeDataSource,
+				name:         name,
+				schema:       resource.Schema,
+				resource:     resource,
+				providerName: opts.ProviderName,
+				providerType: providerType(provider),
+				frontMatter:  site.frontMatter,
+				provider:     provider,
+				site:         site,
+				referencedBy: references[schemaObject{schemaType: typeDataSource, name: name}],
 			},
 		)
 	}
 
 	// Wait for output from the go routines and start building the error list
+	files := []renderedFile{}
 	for i := 0; i < totalGoroutines; i++ {
-		err := <-errChan
-		if err != nil {
-			errors = append(errors, err)
+		file := <-outChan
+		if file.err != nil {
+			errors = append(errors, file.err)
+			continue
 		}
+		files = append(files, file)
+	}
+	// Nothing is written if any of the files failed to generate
+	if len(errors) > 0 {
+		return errors
+	}
+
+	// In check mode, compare the generated output to the files on disk
+	// instead of writing them
+	if opts.Check {
+		return checkFiles(opts, site, files)
 	}
-	return errors
+	// In dry run mode, list the changes instead of making them
+	if opts.DryRun {
+		return dryRunFiles(opts, site, files)
+	}
+	return writeFiles(opts, site, files)
 }
 
 // Usage prints usage information to stdout
@@ -232,31 +673,84 @@ SYNOPSIS
 DESCRIPTION
   autodoc generates the necessary config files for mkdocs and parses
   the provider definition to generate markdown files. The following files
-  are created:
+  are created. Only the site_name, docs_dir, and nav keys of an existing
+  mkdocs.yml are replaced:
 
     * mkdocs.yml       => mkdocs configuration
     * docs/index.md    => Provider documentation
-    * docs/godoc.md    => Wrapper for godoc static files
+    * docs/godoc.md    => Index of the Go API reference
+    * docs/godoc/*.md  => Go API reference of each -go-packages package
     * resources/*.md   => documentation for each resource
     * datasources/*.md => documentation for each data source
 
-  autodoc uses templates to generate the markdown files. autodoc makes
-  the following template associations:
+  autodoc uses templates to generate the markdown files. Each template has
+  a built-in default that is used when the templates directory does not
+  contain it, so user templates over-ride the defaults one file at a time.
+  autodoc makes the following template associations:
 
     * mkdocs.yml       => mkdocs.yml.template
     * docs/index.md    => index.md.template
     * docs/godoc.md    => godoc.md.template
+    * docs/godoc/*.md  => godoc-package.md.template
     * resources/*.md   => resource.md.template
     * datasources/*.md => datasource.md.template
 
   Templates are written in golang stdlib template. See pkg/text/template
-  for more information.
+  for more information. autodoc adds functions for Markdown generation:
+  escapeMarkdown, escapeHTML, tableCell, slug, blockAnchor, code,
+  codeFence, indent, wrap, plural, join, sort, hcl, admonition,
+  resourcePath, resourceLink, dataSourcePath, and dataSourceLink.
+
+  autodoc defines the following built-in partial templates, which can be
+  included from any template and over-ridden by a user template of the same
+  name:
+
+    * _argument.template  => a single argument list item, with its default
+                             and constraints
+    * _blocks.template    => sections for each nested block, to any depth
+    * _arguments.template => the argument list, with _argument.template
+    * _example.template   => the example configuration as an hcl code block
+
+  Templates whose names start with '_' are partials. Including a template
+  that does not exist is an error. A resource or data source is rendered
+  with resources/NAME.md.template or datasources/NAME.md.template from the
+  templates directory if it exists.
+
+  Each guides/NAME.md.template in the templates directory is rendered to
+  docs/guides/NAME.md with the provider model and added to the
+  navigation, ordered by the weight in its front matter.
 
   autodoc exits 0 on succes, 1 on error.
 
 OPTIONS
   -help
     Display usage and exit.
+  -check
+    Do not write any files. Generate the documentation in memory and
+    compare it to the files on disk, printing a unified diff for each
+    out of date file and listing the files that would be created or
+    removed. Exits 1 if the documentation is out of date.
+  -dry-run
+    Do not write any files. List the files that would be created,
+    updated, or removed.
+  -verbose
+    Report whether each generated file was created, updated, unchanged,
+    or removed, in addition to the counts.
+  -dump-templates
+    Write the built-in templates to the templates directory as a starting
+    point for customization and exit. Existing templates are skipped.
+  -lint
+    Do not write any files. Lint the schema documentation and report
+    problems. Exits 1 if any error severity findings are reported.
+    Rules can be suppressed for an attribute with "@NOLINT rule,rule"
+    in its description. Rules (default severity):
+      empty-description (warning), example-on-computed (warning),
+      meta-not-computed (error), unknown-tag (error),
+      missing-summary (warning), unknown-conflicts-with (error),
+      invalid-tag (error), invalid-ref (error), repeated-tag (warning)
+  -compat-update
+    With -compat, write the schema baseline from the provider instead of
+    checking it.
 
 ARGUMENTS
   -provider=NAME
@@ -273,6 +767,47 @@ ARGUMENTS
     template files recursively from this location. This value is relative
     to -root. Defaults to 'templates'
   -templates-ext=TEMPLATES_EXT
-    Extension for template files. Defaults to '.template'.`,
+    Extension for template files. Defaults to '.template'.
+  -profile=PROFILE
+    Output profile. 'mkdocs' generates the files listed above. 'registry'
+    generates the Terraform Registry layout from built-in templates:
+    docs/index.md, docs/resources/*.md and docs/data-sources/*.md, with
+    pages named without the provider prefix and YAML front matter. 'hugo'
+    generates hugo.toml and docs/_index.md, and 'docusaurus' generates
+    sidebars.js, both with YAML front matter. 'bare' generates plain
+    Markdown with docs/README.md linking to every page. Defaults to
+    'mkdocs'.
+  -lint-format=FORMAT
+    Format of the lint report, 'text' or 'json'. Defaults to 'text'.
+  -lint-rules=RULE:SEVERITY[,RULE:SEVERITY...]
+    Over-ride the severity of lint rules. SEVERITY is one of 'error',
+    'warning', or 'off'.
+  -schema-json=PATH
+    Do not write any documentation. Write the provider schema as a JSON
+    document in the shape of 'terraform providers schema -json', with
+    autodoc metadata under "autodoc" keys, to PATH. '-' writes to stdout.
+  -changelog-from=PATH
+    Do not write any documentation. Compare the JSON schema snapshot at
+    PATH to the provider (or to -changelog-to) and write a changelog of
+    the added, removed, renamed, retyped, required, ForceNew, deprecated,
+    and re-defaulted resources and attributes.
+  -changelog-to=PATH
+    JSON schema snapshot to compare to instead of the provider.
+  -changelog-format=FORMAT
+    Format of the changelog, 'markdown' or 'json'. Defaults to 'markdown'.
+    The Markdown is rendered from the changelog.md.template template.
+  -changelog-out=PATH
+    Path to write the changelog to. Defaults to '-' (stdout).
+  -compat=PATH
+    Do not write any documentation. Compare the provider to the schema
+    baseline at PATH and report each change as breaking or non-breaking.
+    Exits 1 if a breaking change is not in the allow file.
+  -compat-allow=PATH
+    JSON allow file of intended breaking changes, each with a
+    justification.
+  -go-packages=DIR[,DIR...]
+    Generate a Markdown API reference page for each Go package directory,
+    parsed in-process with go/doc. 'DIR/...' also selects every package
+    under DIR, skipping vendor and testdata.`,
 	)
 }
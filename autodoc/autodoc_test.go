@@ -0,0 +1,203 @@
+package autodoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Test Helpers
+// -----------------------------------------------------------------------------
+
+// writeTemplates writes the supplied template files (keyed by file name) to
+// a temporary templates directory and returns its path. Names may contain
+// slash separated sub directories. The caller removes the directory.
+func writeTemplates(t *testing.T, templates map[string]string) string {
+	dir, err := ioutil.TempDir("", "autodoc")
+	if err != nil {
+		t.Fatalf("Could not create the templates directory: [%s]", err)
+	}
+	for name, body := range templates {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Could not create the directory of [%s]: [%s]", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatalf("Could not write template [%s]: [%s]", path, err)
+		}
+	}
+	return dir
+}
+
+// testTemplates returns a minimal template set for each output file
+func testTemplates() map[string]string {
+	return map[string]string{
+		"mkdocs.yml.template":    "docs_dir: {{.DocsDir}}\n{{range .Resources}}- {{.}}\n{{end}}",
+		"godoc.md.template":      "# Godoc\n",
+		"index.md.template":      "# {{.Name}}\n",
+		"resource.md.template":   "# {{.Name}}\n{{range .Arguments}}* {{.Name}}\n{{end}}",
+		"datasource.md.template": "# {{.Name}}\n",
+	}
+}
+
+func providerFoo() *schema.Provider {
+	return &schema.Provider{
+		Schema: map[string]*schema.Schema{},
+		ResourcesMap: map[string]*schema.Resource{
+			"foo_bar": resourceFoo(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"foo_baz": resourceFoo(),
+		},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// DocumentWithOptions
+// -----------------------------------------------------------------------------
+
+// Ensures documentation can be generated in-process to a memory filesystem
+func TestDocumentWithOptions_MemoryFileSystem(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	templatesDir := writeTemplates(t, testTemplates())
+	defer os.RemoveAll(templatesDir)
+	errs := DocumentWithOptions(providerFoo(), Options{
+		ProviderName: "foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: templatesDir,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	expectedFiles := []string{
+		"/out/.autodoc-manifest",
+		"/out/docs/datasources/foo_baz.md",
+		"/out/docs/godoc.md",
+		"/out/docs/index.md",
+		"/out/docs/resources/foo_bar.md",
+		"/out/mkdocs.yml",
+	}
+	actualFiles := fs.Files()
+	if strings.Join(expectedFiles, ",") != strings.Join(actualFiles, ",") {
+		t.Fatalf(
+			"DocumentWithOptions did not write the correct files. Expected "+
+				"%v, got %v.",
+			expectedFiles,
+			actualFiles,
+		)
+	}
+
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/foo_bar.md")
+	expectedDoc := "# foo_bar\n* name\n* rule\n"
+	if string(resourceDoc) != expectedDoc {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. "+
+				"Expected [%q], got [%q].",
+			expectedDoc,
+			string(resourceDoc),
+		)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// parseArgs
+// -----------------------------------------------------------------------------
+
+// Ensures command line arguments are parsed into options
+func TestParseArgs(t *testing.T) {
+	args, err := parseArgs([]string{
+		"-provider=foo",
+		"-root=/out",
+		"-template-ext=.tmpl",
+		"-schema-json=-",
+		"-compat=schema.json",
+		"-compat-update",
+		"-dry-run",
+		"-go-packages=./...,../conv",
+	})
+	if err != nil {
+		t.Fatalf("parseArgs returned an error: [%s]", err)
+	}
+	if args.options.ProviderName != "foo" ||
+		args.options.RootDir != "/out" ||
+		args.options.TemplateExt != ".tmpl" ||
+		args.options.SchemaJSON != "-" ||
+		args.options.Compat != "schema.json" ||
+		!args.options.CompatUpdate ||
+		!args.options.DryRun ||
+		strings.Join(args.options.GoPackages, " ") != "./... ../conv" {
+		t.Fatalf(
+			"parseArgs did not return the correct output. Got [%+v].",
+			args.options,
+		)
+	}
+
+	if _, err := parseArgs([]string{"-bogus"}); err == nil {
+		t.Fatalf("parseArgs did not return an error for an unknown argument")
+	}
+}
+
+// Ensures check mode reports stale, missing, and orphaned files without
+// writing anything
+func TestDocumentWithOptions_Check(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	templatesDir := writeTemplates(t, testTemplates())
+	defer os.RemoveAll(templatesDir)
+	opts := Options{
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: templatesDir,
+		FileSystem:   fs,
+	}
+	provider := providerFoo()
+	provider.ResourcesMap["foo_old"] = resourceFoo()
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	// up to date docs pass the check
+	out := bytes.Buffer{}
+	opts.Check = true
+	opts.Out = &out
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("Check returned errors for up to date docs: %v", errs)
+	}
+
+	fs.WriteFile("/out/docs/index.md", []byte("# stale\n"), 0644)
+	fs.WriteFile("/out/docs/resources/notes.md", []byte("# notes\n"), 0644)
+	provider = providerFoo()
+	provider.ResourcesMap["foo_new"] = resourceFoo()
+
+	errs := DocumentWithOptions(provider, opts)
+	if len(errs) != 1 {
+		t.Fatalf("Check did not return an error for out of date docs")
+	}
+	for _, expected := range []string{
+		"--- a/docs/index.md\n+++ b/docs/index.md\n@@ -1 +1 @@\n-# stale\n+# Terraform Provider\n",
+		"Would create: docs/resources/foo_new.md\n",
+		"Would remove: docs/resources/foo_old.md\n",
+		"Warning: docs/resources/notes.md was not generated and is not " +
+			"listed in the manifest. It is kept.\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf(
+				"Check did not return the correct output. Expected [%s] in "+
+					"output:\n%s",
+				expected,
+				out.String(),
+			)
+		}
+	}
+	if _, err := fs.ReadFile("/out/docs/resources/foo_new.md"); err == nil {
+		t.Fatalf("Check wrote a file")
+	}
+}
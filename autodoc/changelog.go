@@ -0,0 +1,724 @@
+package autodoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the changelog, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Formats of the schema changelog
+const (
+	// Markdown rendered from the changelog.md template
+	ChangelogFormatMarkdown = "markdown"
+	// JSON document listing each change
+	ChangelogFormatJSON = "json"
+)
+
+// Built-in template association for the Markdown changelog. The template
+// extension from the options is appended to this name, so a user template of
+// the same name over-rides the built-in one.
+const changelogMdTemplate = "changelog.md"
+
+// Path of the changelog page relative to the docs directory. If the
+// changelog was written there, the page is added to the mkdocs navigation.
+const changelogPage = "changelog.md"
+
+// Kinds of schema changes between two schema snapshots
+const (
+	// A resource or data source was added
+	changeAdded = "added"
+	// A resource or data source was removed
+	changeRemoved = "removed"
+	// An attribute or nested block was added
+	changeAttributeAdded = "attribute-added"
+	// An attribute or nested block was removed
+	changeAttributeRemoved = "attribute-removed"
+	// An attribute or nested block was removed and another one of the same
+	// type was added in the same block
+	changeAttributeRenamed = "attribute-renamed"
+	// The type of an attribute or nested block changed
+	changeTypeChanged = "type-changed"
+	// An optional attribute or nested block became required
+	changeMadeRequired = "made-required"
+	// An attribute or nested block started forcing a new resource
+	changeMadeForceNew = "made-force-new"
+	// A resource, data source, attribute, or nested block was deprecated
+	changeDeprecated = "deprecated"
+	// The default value of an attribute changed
+	changeDefaultChanged = "default-changed"
+	// The maximum number of items of an attribute or nested block was
+	// lowered, or set where there was none
+	changeMaxItemsTightened = "max-items-tightened"
+)
+
+// Object types of schema changes
+const (
+	objectProvider   = "provider"
+	objectResource   = "resource"
+	objectDataSource = "data_source"
+)
+
+// -----------------------------------------------------------------------------
+// Changelog Definition
+// -----------------------------------------------------------------------------
+
+// A change to the schema between two schema snapshots
+type schemaChange struct {
+	// Kind of change, one of the changeXxx constants
+	Kind string `json:"kind"`
+	// Type of the changed object, one of the objectXxx constants
+	ObjectType string `json:"object_type"`
+	// Name of the changed provider, resource, or data source
+	Object string `json:"object"`
+	// Dot separated path of the changed attribute or nested block (ie:
+	// "rule.priority"). Empty if the change is to the object itself.
+	Attribute string `json:"attribute,omitempty"`
+	// Value before and after the change, if the kind of change has one (ie:
+	// the old and new type)
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+	// Human readable description of the change, in Markdown
+	Message string `json:"message"`
+	// Whether or not the change can break existing configurations. See
+	// isBreakingChange.
+	Breaking bool `json:"breaking"`
+}
+
+// The JSON changelog document
+type changelogJSON struct {
+	Changes []schemaChange `json:"changes"`
+}
+
+// Template data for the Markdown changelog
+type changelogData struct {
+	// Name of the provider being documented
+	ProviderName string
+	// Names of the added and removed resources and data sources
+	ResourcesAdded     []string
+	ResourcesRemoved   []string
+	DataSourcesAdded   []string
+	DataSourcesRemoved []string
+	// Changes to the provider, resources, and data sources that exist in
+	// both snapshots, grouped by object
+	Objects []changelogObject
+}
+
+// Changes to one provider, resource, or data source
+type changelogObject struct {
+	// Name of the provider, resource, or data source
+	Name string
+	// Display name of the object type: "Provider", "Resource", or
+	// "Data Source"
+	Type string
+	// List of changes, in the order they were found
+	Changes []schemaChange
+}
+
+// An attribute or nested block flattened out of a JSON schema block for
+// comparison
+type changelogAttribute struct {
+	// Dot separated path of the parent block. Empty at the root.
+	parent string
+	// Whether or not this is a nested block
+	block bool
+	// Type of the attribute (compact cty JSON type) or nested block (ie:
+	// "list block")
+	attrType string
+	required bool
+	forceNew bool
+	// Maximum number of items. 0 if there is no maximum.
+	maxItems int
+	// Deprecation message. A single space if it is deprecated without a
+	// message, and empty if it is not deprecated.
+	deprecated  string
+	defaultVal  string
+	description string
+}
+
+// -----------------------------------------------------------------------------
+// Changelog Utility Functions
+// -----------------------------------------------------------------------------
+
+// writeChangelog compares the schema snapshot at the options' ChangelogFrom
+// path to the snapshot at the ChangelogTo path, or to the provider if
+// ChangelogTo is empty, and writes the changelog in the configured format to
+// the ChangelogOut path. Returns a list of errors. If this list is empty, the
+// changelog was written.
+func writeChangelog(provider *schema.Provider, opts Options) []error {
+	errors := []error{}
+
+	oldDoc, oldErr := readSchemaSnapshot(opts, opts.ChangelogFrom)
+	if oldErr != nil {
+		errors = append(errors, oldErr)
+		return errors
+	}
+	newDoc := providerSchemas(provider, opts)
+	if opts.ChangelogTo != "" {
+		var newErr error
+		if newDoc, newErr = readSchemaSnapshot(opts, opts.ChangelogTo); newErr != nil {
+			errors = append(errors, newErr)
+			return errors
+		}
+	}
+
+	changes, diffErr := diffSchemas(oldDoc, newDoc)
+	if diffErr != nil {
+		errors = append(errors, diffErr)
+		return errors
+	}
+
+	var content []byte
+	switch opts.ChangelogFormat {
+	case ChangelogFormatMarkdown:
+		rendered, renderErr := renderChangelog(opts, changes)
+		if renderErr != nil {
+			errors = append(errors, renderErr)
+			return errors
+		}
+		content = rendered
+	case ChangelogFormatJSON:
+		encoded, _ := json.MarshalIndent(changelogJSON{Changes: changes}, "", "  ")
+		content = append(encoded, '\n')
+	default:
+		errors = append(errors, fmt.Errorf(
+			"Unrecognized changelog format [%s]. Expected [%s] or [%s].",
+			opts.ChangelogFormat,
+			ChangelogFormatMarkdown,
+			ChangelogFormatJSON,
+		))
+		return errors
+	}
+
+	if opts.ChangelogOut == stdoutPath {
+		if _, writeErr := opts.Out.Write(content); writeErr != nil {
+			errors = append(errors, writeErr)
+		}
+		return errors
+	}
+	if writeErr := opts.FileSystem.WriteFile(opts.ChangelogOut, content, outputFileMode); writeErr != nil {
+		errors = append(errors, fmt.Errorf(
+			"Cannot generate [%s]. Failed to write file. Error: [%s]",
+			opts.ChangelogOut,
+			writeErr.Error(),
+		))
+	}
+	return errors
+}
+
+// readSchemaSnapshot reads and decodes a JSON schema document written by
+// -schema-json (or by `terraform providers schema -json`) from the options'
+// filesystem.
+func readSchemaSnapshot(opts Options, path string) (*providerSchemasJSON, error) {
+	content, readErr := opts.FileSystem.ReadFile(path)
+	if readErr != nil {
+		return nil, fmt.Errorf(
+			"Cannot read schema snapshot [%s]. Error: [%s]",
+			path,
+			readErr.Error(),
+		)
+	}
+	doc := &providerSchemasJSON{}
+	if decodeErr := json.Unmarshal(content, doc); decodeErr != nil {
+		return nil, fmt.Errorf(
+			"Cannot read schema snapshot [%s]. The file is not a JSON "+
+				"schema document. Error: [%s]",
+			path,
+			decodeErr.Error(),
+		)
+	}
+	return doc, nil
+}
+
+// renderChangelog renders the Markdown changelog from the changelog.md
+// template
+func renderChangelog(opts Options, changes []schemaChange) ([]byte, error) {
+	templates, tmplErr := parseTemplates(opts)
+	if tmplErr != nil {
+		return nil, tmplErr
+	}
+
+	data := changelogData{ProviderName: opts.ProviderName}
+	objects := map[string]int{}
+	for _, change := range changes {
+		switch {
+		case change.Kind == changeAdded && change.ObjectType == objectResource:
+			data.ResourcesAdded = append(data.ResourcesAdded, change.Object)
+		case change.Kind == changeRemoved && change.ObjectType == objectResource:
+			data.ResourcesRemoved = append(data.ResourcesRemoved, change.Object)
+		case change.Kind == changeAdded:
+			data.DataSourcesAdded = append(data.DataSourcesAdded, change.Object)
+		case change.Kind == changeRemoved:
+			data.DataSourcesRemoved = append(data.DataSourcesRemoved, change.Object)
+		default:
+			key := change.ObjectType + "/" + change.Object
+			idx, ok := objects[key]
+			if !ok {
+				idx = len(data.Objects)
+				objects[key] = idx
+				data.Objects = append(data.Objects, changelogObject{
+					Name: change.Object,
+					Type: objectTypeTitle(change.ObjectType),
+				})
+			}
+			data.Objects[idx].Changes = append(data.Objects[idx].Changes, change)
+		}
+	}
+
+	out := bytes.Buffer{}
+	if execErr := templates.ExecuteTemplate(&out, changelogMdTemplate+opts.TemplateExt, data); execErr != nil {
+		return nil, execErr
+	}
+	return out.Bytes(), nil
+}
+
+// diffSchemas returns the changes from the old to the new JSON schema
+// document: changes to the provider first, then to resources, then to data
+// sources, each in alphabetical order. Only the first provider of each
+// document (by name) is compared.
+func diffSchemas(oldDoc *providerSchemasJSON, newDoc *providerSchemasJSON) ([]schemaChange, error) {
+	_, oldProvider := firstProviderSchema(oldDoc)
+	newName, newProvider := firstProviderSchema(newDoc)
+	if oldProvider == nil || newProvider == nil {
+		return nil, fmt.Errorf("Cannot compare schemas. A schema document has no provider schema.")
+	}
+
+	changes := []schemaChange{}
+	if oldProvider.Provider != nil && newProvider.Provider != nil {
+		changes = append(changes, diffBlocks(
+			objectProvider, newName,
+			oldProvider.Provider.Block, newProvider.Provider.Block,
+		)...)
+	}
+	changes = append(changes, diffSchemaMaps(
+		objectResource,
+		oldProvider.ResourceSchemas, newProvider.ResourceSchemas,
+	)...)
+	changes = append(changes, diffSchemaMaps(
+		objectDataSource,
+		oldProvider.DataSourceSchemas, newProvider.DataSourceSchemas,
+	)...)
+	return changes, nil
+}
+
+// firstProviderSchema returns the name and schema of the first provider of
+// the document, by name. nil is returned if the document has no provider.
+func firstProviderSchema(doc *providerSchemasJSON) (string, *providerSchemaJSON) {
+	names := []string{}
+	for name := range doc.ProviderSchemas {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return "", nil
+	}
+	sort.Strings(names)
+	return names[0], doc.ProviderSchemas[names[0]]
+}
+
+// diffSchemaMaps returns the changes from the old to the new resource or data
+// source schemas. objectType should be objectResource or objectDataSource.
+func diffSchemaMaps(objectType string, oldMap map[string]*schemaJSON, newMap map[string]*schemaJSON) []schemaChange {
+	changes := []schemaChange{}
+	for _, name := range unionStrings(schemaNames(oldMap), schemaNames(newMap)) {
+		oldSchema, inOld := oldMap[name]
+		newSchema, inNew := newMap[name]
+		title := objectTypeTitle(objectType)
+		switch {
+		case !inOld:
+			changes = append(changes, schemaChange{
+				Kind:       changeAdded,
+				ObjectType: objectType,
+				Object:     name,
+				Message:    fmt.Sprintf("%s `%s` was added.", title, name),
+			})
+		case !inNew:
+			changes = append(changes, schemaChange{
+				Kind:       changeRemoved,
+				ObjectType: objectType,
+				Object:     name,
+				Message:    fmt.Sprintf("%s `%s` was removed.", title, name),
+				Breaking:   isBreakingChange(changeRemoved, changelogAttribute{}),
+			})
+		default:
+			oldDeprecated := schemaDeprecation(oldSchema)
+			newDeprecated := schemaDeprecation(newSchema)
+			if oldDeprecated == "" && newDeprecated != "" {
+				changes = append(changes, schemaChange{
+					Kind:       changeDeprecated,
+					ObjectType: objectType,
+					Object:     name,
+					New:        newDeprecated,
+					Message: fmt.Sprintf(
+						"%s `%s` is deprecated.%s",
+						title, name, deprecationSuffix(newDeprecated),
+					),
+				})
+			}
+			changes = append(changes, diffBlocks(objectType, name, oldSchema.Block, newSchema.Block)...)
+		}
+	}
+	return changes
+}
+
+// diffBlocks returns the changes from the old to the new root block of a
+// provider, resource, or data source, including all nested blocks
+func diffBlocks(objectType string, object string, oldBlock *blockJSON, newBlock *blockJSON) []schemaChange {
+	oldAttrs := flattenBlock(oldBlock, "", map[string]changelogAttribute{})
+	newAttrs := flattenBlock(newBlock, "", map[string]changelogAttribute{})
+
+	changes := []schemaChange{}
+	change := func(kind string, path string, attr changelogAttribute, oldVal string, newVal string, format string, args ...interface{}) {
+		noun := "Attribute"
+		if attr.block {
+			noun = "Block"
+		}
+		breaking := isBreakingChange(kind, attr)
+		// a required attribute only breaks configurations that already
+		// contain its block
+		if kind == changeAttributeAdded && !inRequiredBlocks(attr, oldAttrs, newAttrs) {
+			breaking = false
+		}
+		changes = append(changes, schemaChange{
+			Kind:       kind,
+			ObjectType: objectType,
+			Object:     object,
+			Attribute:  path,
+			Old:        oldVal,
+			New:        newVal,
+			Message:    fmt.Sprintf("%s `%s` "+format, append([]interface{}{noun, path}, args...)...),
+			Breaking:   breaking,
+		})
+	}
+
+	paths := unionStrings(attributePaths(oldAttrs), attributePaths(newAttrs))
+
+	// attributes that exist in only one of the blocks
+	added := []string{}
+	removed := []string{}
+	for _, path := range paths {
+		if _, ok := oldAttrs[path]; !ok {
+			added = append(added, path)
+		} else if _, ok := newAttrs[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	renamed := pairRenames(removed, added, oldAttrs, newAttrs)
+	for _, path := range removed {
+		if to, ok := renamed[path]; ok {
+			change(
+				changeAttributeRenamed, path, oldAttrs[path], path, to,
+				"was removed and `%s` was added with the same type. It "+
+					"looks like a rename.",
+				to,
+			)
+			continue
+		}
+		change(changeAttributeRemoved, path, oldAttrs[path], "", "", "was removed.")
+	}
+	renamedTo := map[string]bool{}
+	for _, to := range renamed {
+		renamedTo[to] = true
+	}
+	for _, path := range added {
+		if renamedTo[path] {
+			continue
+		}
+		if newAttrs[path].required {
+			change(changeAttributeAdded, path, newAttrs[path], "", "", "was added and is required.")
+			continue
+		}
+		change(changeAttributeAdded, path, newAttrs[path], "", "", "was added.")
+	}
+
+	// attributes that exist in both blocks
+	for _, path := range paths {
+		oldAttr, inOld := oldAttrs[path]
+		newAttr, inNew := newAttrs[path]
+		if !inOld || !inNew {
+			continue
+		}
+		if oldAttr.attrType != newAttr.attrType {
+			change(
+				changeTypeChanged, path, newAttr, oldAttr.attrType, newAttr.attrType,
+				"changed type from `%s` to `%s`.", oldAttr.attrType, newAttr.attrType,
+			)
+		}
+		if !oldAttr.required && newAttr.required {
+			change(changeMadeRequired, path, newAttr, "", "", "is now required.")
+		}
+		if !oldAttr.forceNew && newAttr.forceNew {
+			change(changeMadeForceNew, path, newAttr, "", "", "now forces a new resource when changed.")
+		}
+		if oldAttr.deprecated == "" && newAttr.deprecated != "" {
+			change(
+				changeDeprecated, path, newAttr, "", newAttr.deprecated,
+				"is deprecated.%s", deprecationSuffix(newAttr.deprecated),
+			)
+		}
+		if oldAttr.defaultVal != newAttr.defaultVal {
+			change(
+				changeDefaultChanged, path, newAttr, oldAttr.defaultVal, newAttr.defaultVal,
+				"default changed from %s to %s.",
+				displayDefault(oldAttr.defaultVal), displayDefault(newAttr.defaultVal),
+			)
+		}
+		if newAttr.maxItems > 0 && (oldAttr.maxItems == 0 || newAttr.maxItems < oldAttr.maxItems) {
+			oldMax := "no maximum"
+			if oldAttr.maxItems > 0 {
+				oldMax = fmt.Sprintf("`%d`", oldAttr.maxItems)
+			}
+			change(
+				changeMaxItemsTightened, path, newAttr,
+				strconv.Itoa(oldAttr.maxItems), strconv.Itoa(newAttr.maxItems),
+				"now allows at most `%d` items, down from %s.", newAttr.maxItems, oldMax,
+			)
+		}
+	}
+	return changes
+}
+
+// flattenBlock adds the attributes and nested blocks of a JSON schema block,
+// to arbitrary depth, to the flattened map by their dot separated path.
+// parentPath is the path of the block, or the empty string for the root.
+func flattenBlock(block *blockJSON, parentPath string, flattened map[string]changelogAttribute) map[string]changelogAttribute {
+	if block == nil {
+		return flattened
+	}
+	for name, attr := range block.Attributes {
+		compact := bytes.Buffer{}
+		if compactErr := json.Compact(&compact, attr.AttributeType); compactErr != nil {
+			compact.Reset()
+			compact.Write(attr.AttributeType)
+		}
+		flat := changelogAttribute{
+			parent:      parentPath,
+			attrType:    compact.String(),
+			required:    attr.Required,
+			description: attr.Description,
+		}
+		if attr.Deprecated {
+			flat.deprecated = " "
+		}
+		if attr.Autodoc != nil {
+			flat.forceNew = attr.Autodoc.ForceNew
+			flat.maxItems = attr.Autodoc.MaxItems
+			flat.defaultVal = attr.Autodoc.Default
+			if attr.Autodoc.Deprecated != "" {
+				flat.deprecated = attr.Autodoc.Deprecated
+			}
+		}
+		flattened[blockPath(parentPath, name)] = flat
+	}
+	for name, blockType := range block.BlockTypes {
+		path := blockPath(parentPath, name)
+		flat := changelogAttribute{
+			parent:   parentPath,
+			block:    true,
+			attrType: blockType.NestingMode + " block",
+			required: blockType.MinItems > 0,
+			maxItems: blockType.MaxItems,
+		}
+		if blockType.Block != nil {
+			flat.description = blockType.Block.Description
+			if blockType.Block.Deprecated {
+				flat.deprecated = " "
+			}
+		}
+		if blockType.Autodoc != nil {
+			flat.forceNew = blockType.Autodoc.ForceNew
+			if blockType.Autodoc.Deprecated != "" {
+				flat.deprecated = blockType.Autodoc.Deprecated
+			}
+		}
+		flattened[path] = flat
+		flattenBlock(blockType.Block, path, flattened)
+	}
+	return flattened
+}
+
+// pairRenames pairs removed attributes with added attributes that look like
+// renames: they are in the same parent block and have the same type, and
+// either have the same non-empty description or are the only removed and
+// added attributes of that type in the block. Returns the new path of each
+// renamed attribute by its old path.
+func pairRenames(removed []string, added []string, oldAttrs map[string]changelogAttribute, newAttrs map[string]changelogAttribute) map[string]string {
+	renamed := map[string]string{}
+	paired := map[string]bool{}
+	candidates := func(attr changelogAttribute, paths []string, attrs map[string]changelogAttribute) []string {
+		matches := []string{}
+		for _, path := range paths {
+			other := attrs[path]
+			if other.parent == attr.parent && other.attrType == attr.attrType && other.block == attr.block {
+				matches = append(matches, path)
+			}
+		}
+		return matches
+	}
+
+	for _, from := range removed {
+		oldAttr := oldAttrs[from]
+		matches := []string{}
+		for _, to := range candidates(oldAttr, added, newAttrs) {
+			if !paired[to] {
+				matches = append(matches, to)
+			}
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		to := ""
+		if oldAttr.description != "" {
+			for _, match := range matches {
+				if newAttrs[match].description == oldAttr.description {
+					to = match
+					break
+				}
+			}
+		}
+		if to == "" && len(matches) == 1 && len(candidates(oldAttr, removed, oldAttrs)) == 1 {
+			to = matches[0]
+		}
+		if to != "" {
+			renamed[from] = to
+			paired[to] = true
+		}
+	}
+	return renamed
+}
+
+// schemaDeprecation returns the deprecation message of a resource or data
+// source schema. A single space is returned if it is deprecated without a
+// message, and the empty string if it is not deprecated.
+func schemaDeprecation(s *schemaJSON) string {
+	if s.Autodoc != nil && s.Autodoc.Deprecated != "" {
+		return s.Autodoc.Deprecated
+	}
+	if s.Block != nil && s.Block.Deprecated {
+		return " "
+	}
+	return ""
+}
+
+// deprecationSuffix returns the deprecation message to append to a change
+// message, with a leading space. Empty if there is no message.
+func deprecationSuffix(message string) string {
+	if strings.TrimSpace(message) == "" {
+		return ""
+	}
+	return " " + strings.TrimSpace(message)
+}
+
+// displayDefault formats a default value for a change message
+func displayDefault(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return "`" + value + "`"
+}
+
+// objectTypeTitle returns the display name of a change object type
+func objectTypeTitle(objectType string) string {
+	switch objectType {
+	case objectProvider:
+		return "Provider"
+	case objectResource:
+		return "Resource"
+	default:
+		return "Data Source"
+	}
+}
+
+// schemaNames returns the names of the schemas in the map
+func schemaNames(schemas map[string]*schemaJSON) []string {
+	names := []string{}
+	for name := range schemas {
+		names = append(names, name)
+	}
+	return names
+}
+
+// inRequiredBlocks returns whether or not every block the attribute is
+// nested under existed in the old schema and is required in the new one, so
+// every configuration written against the old schema contains the block.
+// Attributes at the root of the schema are in no block.
+func inRequiredBlocks(attr changelogAttribute, oldAttrs map[string]changelogAttribute, newAttrs map[string]changelogAttribute) bool {
+	for parent := attr.parent; parent != ""; parent = newAttrs[parent].parent {
+		if _, ok := oldAttrs[parent]; !ok || !newAttrs[parent].required {
+			return false
+		}
+	}
+	return true
+}
+
+// attributePaths returns the paths of the flattened attributes in the map
+func attributePaths(attrs map[string]changelogAttribute) []string {
+	paths := []string{}
+	for path := range attrs {
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// unionStrings returns the sorted union of two lists of strings, without
+// duplicates
+func unionStrings(a []string, b []string) []string {
+	seen := map[string]bool{}
+	union := []string{}
+	for _, list := range [][]string{a, b} {
+		for _, value := range list {
+			if !seen[value] {
+				seen[value] = true
+				union = append(union, value)
+			}
+		}
+	}
+	sort.Strings(union)
+	return union
+}
+
+// -----------------------------------------------------------------------------
+// Built-in Changelog Template
+// -----------------------------------------------------------------------------
+
+// Body of the built-in Markdown changelog
+const changelogMd = `# Schema Changelog
+{{if not (or .ResourcesAdded .ResourcesRemoved .DataSourcesAdded .DataSourcesRemoved .Objects)}}
+No schema changes.
+{{end -}}
+{{if .ResourcesAdded}}
+## New Resources
+
+{{range .ResourcesAdded}}* ` + "`{{.}}`" + `
+{{end}}{{end -}}
+{{if .DataSourcesAdded}}
+## New Data Sources
+
+{{range .DataSourcesAdded}}* ` + "`{{.}}`" + `
+{{end}}{{end -}}
+{{if .ResourcesRemoved}}
+## Removed Resources
+
+{{range .ResourcesRemoved}}* ` + "`{{.}}`" + `
+{{end}}{{end -}}
+{{if .DataSourcesRemoved}}
+## Removed Data Sources
+
+{{range .DataSourcesRemoved}}* ` + "`{{.}}`" + `
+{{end}}{{end -}}
+{{range .Objects}}
+## ` + "`{{.Name}}`" + ` ({{.Type}})
+
+{{range .Changes}}* {{if .Breaking}}**Breaking:** {{end}}{{.Message}}
+{{end}}{{end -}}
+`
@@ -0,0 +1,177 @@
+package autodoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Test Helpers
+// -----------------------------------------------------------------------------
+
+// providerChangelog returns the provider before and after a set of schema
+// changes
+func providerChangelog() (*schema.Provider, *schema.Provider) {
+	before := providerFoo()
+	before.ResourcesMap["foo_bar"].Schema["size"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+		Default:  1,
+	}
+	before.ResourcesMap["foo_bar"].Schema["mode"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+
+	after := providerFoo()
+	delete(after.DataSourcesMap, "foo_baz")
+	after.ResourcesMap["foo_new"] = resourceFoo()
+	after.ResourcesMap["foo_bar"].DeprecationMessage = "Use foo_new."
+	resource := after.ResourcesMap["foo_bar"].Schema
+	resource["name"].ForceNew = true
+	resource["size"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "2",
+	}
+	resource["mode"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	rule := resource["rule"].Elem.(*schema.Resource).Schema
+	rule["weight"] = rule["priority"]
+	delete(rule, "priority")
+	return before, after
+}
+
+// -----------------------------------------------------------------------------
+// Schema Changelog
+// -----------------------------------------------------------------------------
+
+// Ensures each kind of schema change is reported between a snapshot and the
+// provider
+func TestDocumentWithOptions_ChangelogJSON(t *testing.T) {
+	before, after := providerChangelog()
+	snapshot, _ := marshalSchemaJSON(providerSchemas(before, Options{}))
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/out/schema.json", snapshot, 0644)
+
+	out := bytes.Buffer{}
+	errs := DocumentWithOptions(after, Options{
+		RootDir:         "/out",
+		ChangelogFrom:   "/out/schema.json",
+		ChangelogFormat: ChangelogFormatJSON,
+		FileSystem:      fs,
+		Out:             &out,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	changelog := changelogJSON{}
+	if err := json.Unmarshal(out.Bytes(), &changelog); err != nil {
+		t.Fatalf("The changelog does not decode: [%s]", err)
+	}
+	actual := []string{}
+	for _, change := range changelog.Changes {
+		actual = append(actual, strings.Join([]string{
+			change.Kind, change.ObjectType, change.Object, change.Attribute,
+			change.Old, change.New,
+		}, "|"))
+	}
+	expected := []string{
+		"deprecated|resource|foo_bar|||Use foo_new.",
+		"attribute-renamed|resource|foo_bar|rule.priority|rule.priority|rule.weight",
+		"made-required|resource|foo_bar|mode||",
+		"made-force-new|resource|foo_bar|name||",
+		`type-changed|resource|foo_bar|size|"number"|"string"`,
+		`default-changed|resource|foo_bar|size|1|"2"`,
+		"added|resource|foo_new|||",
+		"removed|data_source|foo_baz|||",
+	}
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf(
+			"The changelog did not return the correct changes. Expected:\n"+
+				"%s\ngot:\n%s",
+			strings.Join(expected, "\n"),
+			strings.Join(actual, "\n"),
+		)
+	}
+}
+
+// Ensures the Markdown changelog groups the changes between two snapshots
+func TestDocumentWithOptions_ChangelogMarkdown(t *testing.T) {
+	before, after := providerChangelog()
+	beforeSnapshot, _ := marshalSchemaJSON(providerSchemas(before, Options{}))
+	afterSnapshot, _ := marshalSchemaJSON(providerSchemas(after, Options{}))
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/out/before.json", beforeSnapshot, 0644)
+	fs.WriteFile("/out/after.json", afterSnapshot, 0644)
+
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:       "/out",
+		TemplatesDir:  "/does/not/exist",
+		ChangelogFrom: "/out/before.json",
+		ChangelogTo:   "/out/after.json",
+		ChangelogOut:  "/out/docs/changelog.md",
+		FileSystem:    fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	changelog, _ := fs.ReadFile("/out/docs/changelog.md")
+	for _, expected := range []string{
+		"# Schema Changelog\n\n## New Resources\n\n* `foo_new`\n",
+		"## Removed Data Sources\n\n* `foo_baz`\n",
+		"## `foo_bar` (Resource)\n\n* Resource `foo_bar` is deprecated. Use foo_new.\n",
+		"* **Breaking:** Attribute `rule.priority` was removed and `rule.weight` was added " +
+			"with the same type. It looks like a rename.\n",
+		"\n* Attribute `size` default changed from `1` to `\"2\"`.\n",
+	} {
+		if !strings.Contains(string(changelog), expected) {
+			t.Fatalf(
+				"The changelog did not return the correct output. Expected "+
+					"[%s] in output:\n%s",
+				expected,
+				string(changelog),
+			)
+		}
+	}
+}
+
+// Ensures a changelog page in the docs directory is added to the mkdocs
+// navigation
+func TestDocumentWithOptions_ChangelogNav(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	opts := Options{
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+	}
+	if errs := DocumentWithOptions(providerFoo(), opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	expected := "  - Changelog: 'changelog.md'\n"
+	if mkdocs, _ := fs.ReadFile("/out/mkdocs.yml"); strings.Contains(string(mkdocs), expected) {
+		t.Fatalf("DocumentWithOptions added a missing changelog to the navigation:\n%s", string(mkdocs))
+	}
+
+	fs.WriteFile("/out/docs/changelog.md", []byte("# Schema Changelog\n"), 0644)
+	if errs := DocumentWithOptions(providerFoo(), opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	if mkdocs, _ := fs.ReadFile("/out/mkdocs.yml"); !strings.Contains(string(mkdocs), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"[%s] in output:\n%s",
+			expected,
+			string(mkdocs),
+		)
+	}
+}
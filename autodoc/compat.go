@@ -0,0 +1,206 @@
+package autodoc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the compatibility check, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Kinds of schema changes that break configurations or references written
+// against the old schema. Adding a required attribute is also breaking unless
+// it is nested under a new or optional block; see isBreakingChange and
+// inRequiredBlocks.
+var breakingChangeKinds = map[string]bool{
+	changeRemoved:           true,
+	changeAttributeRemoved:  true,
+	changeAttributeRenamed:  true,
+	changeTypeChanged:       true,
+	changeMadeRequired:      true,
+	changeMadeForceNew:      true,
+	changeMaxItemsTightened: true,
+}
+
+// -----------------------------------------------------------------------------
+// Compatibility Check Data Structs
+// -----------------------------------------------------------------------------
+
+// The allow file of the compatibility check. Lists the breaking changes that
+// are intended, each with the reason it is acceptable.
+type compatAllowFile struct {
+	Allow []compatAllowEntry `json:"allow"`
+}
+
+// A breaking change that is allowed. The kind, object type, object, and
+// attribute must match the change exactly, as they are reported in the JSON
+// changelog.
+type compatAllowEntry struct {
+	Kind       string `json:"kind"`
+	ObjectType string `json:"object_type"`
+	Object     string `json:"object"`
+	// Dot separated path of the attribute. Empty for changes to the object
+	// itself.
+	Attribute string `json:"attribute,omitempty"`
+	// Why the breaking change is acceptable (ie: a link to the upgrade
+	// guide). Required.
+	Justification string `json:"justification"`
+}
+
+// -----------------------------------------------------------------------------
+// Compatibility Check Utility Functions
+// -----------------------------------------------------------------------------
+
+// checkCompat compares the provider to the schema baseline at the options'
+// Compat path and reports each change to the options' output as breaking,
+// allowed, or non-breaking. If CompatUpdate is set, the baseline is written
+// from the provider instead. Returns a list of errors. If this list is empty,
+// there are no breaking changes that are not in the allow file.
+func checkCompat(provider *schema.Provider, opts Options) []error {
+	errors := []error{}
+
+	if opts.CompatUpdate {
+		content, marshalErr := marshalSchemaJSON(providerSchemas(provider, opts))
+		if marshalErr != nil {
+			errors = append(errors, marshalErr)
+			return errors
+		}
+		if writeErr := opts.FileSystem.WriteFile(opts.Compat, content, outputFileMode); writeErr != nil {
+			errors = append(errors, fmt.Errorf(
+				"Cannot generate [%s]. Failed to write file. Error: [%s]",
+				opts.Compat,
+				writeErr.Error(),
+			))
+		}
+		return errors
+	}
+
+	baseline, baselineErr := readSchemaSnapshot(opts, opts.Compat)
+	if baselineErr != nil {
+		errors = append(errors, fmt.Errorf(
+			"%s Use %s to capture the baseline.",
+			baselineErr.Error(),
+			argCompatUpdate,
+		))
+		return errors
+	}
+	allowed, allowErr := readCompatAllowFile(opts)
+	if allowErr != nil {
+		errors = append(errors, allowErr)
+		return errors
+	}
+
+	changes, diffErr := diffSchemas(baseline, providerSchemas(provider, opts))
+	if diffErr != nil {
+		errors = append(errors, diffErr)
+		return errors
+	}
+
+	used := make([]bool, len(allowed))
+	breaking := 0
+	for _, change := range changes {
+		object := change.Object
+		if change.Attribute != "" {
+			object += "." + change.Attribute
+		}
+		if !change.Breaking {
+			fmt.Fprintf(opts.Out, "%s: non-breaking: %s [%s]\n", object, change.Message, change.Kind)
+			continue
+		}
+		if idx := findCompatAllowEntry(allowed, change); idx >= 0 {
+			used[idx] = true
+			fmt.Fprintf(
+				opts.Out, "%s: allowed: %s [%s] Justification: %s\n",
+				object, change.Message, change.Kind, allowed[idx].Justification,
+			)
+			continue
+		}
+		breaking++
+		fmt.Fprintf(opts.Out, "%s: breaking: %s [%s]\n", object, change.Message, change.Kind)
+	}
+	for idx, entry := range allowed {
+		if !used[idx] {
+			fmt.Fprintf(
+				opts.Out, "Unused allow file entry [%s %s %s %s]. It can be removed.\n",
+				entry.Kind, entry.ObjectType, entry.Object, entry.Attribute,
+			)
+		}
+	}
+
+	if breaking > 0 {
+		errors = append(errors, fmt.Errorf(
+			"Compatibility check failed with [%d] breaking change(s) since the "+
+				"baseline [%s]. Add intended changes to the allow file with a "+
+				"justification.",
+			breaking,
+			opts.Compat,
+		))
+	}
+	return errors
+}
+
+// readCompatAllowFile reads the allow file at the options' CompatAllow path.
+// An empty list is returned if no allow file is configured. An error is
+// returned if the file cannot be read or an entry has no justification.
+func readCompatAllowFile(opts Options) ([]compatAllowEntry, error) {
+	if opts.CompatAllow == "" {
+		return []compatAllowEntry{}, nil
+	}
+	content, readErr := opts.FileSystem.ReadFile(opts.CompatAllow)
+	if readErr != nil {
+		return nil, fmt.Errorf(
+			"Cannot read allow file [%s]. Error: [%s]",
+			opts.CompatAllow,
+			readErr.Error(),
+		)
+	}
+	allowFile := compatAllowFile{}
+	if decodeErr := json.Unmarshal(content, &allowFile); decodeErr != nil {
+		return nil, fmt.Errorf(
+			"Cannot read allow file [%s]. Error: [%s]",
+			opts.CompatAllow,
+			decodeErr.Error(),
+		)
+	}
+	for idx, entry := range allowFile.Allow {
+		if entry.Justification == "" {
+			return nil, fmt.Errorf(
+				"Allow file entry [%d] of [%s] has no justification.",
+				idx,
+				opts.CompatAllow,
+			)
+		}
+	}
+	return allowFile.Allow, nil
+}
+
+// findCompatAllowEntry returns the index of the allow file entry that matches
+// the change, or -1 if none does
+func findCompatAllowEntry(allowed []compatAllowEntry, change schemaChange) int {
+	for idx, entry := range allowed {
+		if entry.Kind == change.Kind &&
+			entry.ObjectType == change.ObjectType &&
+			entry.Object == change.Object &&
+			entry.Attribute == change.Attribute {
+			return idx
+		}
+	}
+	return -1
+}
+
+// isBreakingChange returns whether or not a change of the kind can break
+// configurations or references written against the old schema. attr is the
+// changed attribute, in the new schema if it exists there. Added attributes
+// are further checked against their blocks by diffBlocks.
+func isBreakingChange(kind string, attr changelogAttribute) bool {
+	if kind == changeAttributeAdded {
+		return attr.required
+	}
+	return breakingChangeKinds[kind]
+}
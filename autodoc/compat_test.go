@@ -0,0 +1,199 @@
+package autodoc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Compatibility Check
+// -----------------------------------------------------------------------------
+
+// Ensures the baseline is written from the provider with -compat-update
+func TestDocumentWithOptions_CompatUpdate(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:      "/out",
+		Compat:       "/out/schema.json",
+		CompatUpdate: true,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	expected, _ := marshalSchemaJSON(providerSchemas(providerFoo(), Options{}))
+	actual, _ := fs.ReadFile("/out/schema.json")
+	if string(actual) != string(expected) {
+		t.Fatalf(
+			"The baseline did not return the correct output. Expected:\n%s\ngot:\n%s",
+			string(expected),
+			string(actual),
+		)
+	}
+}
+
+// Ensures breaking changes fail the check unless they are in the allow file
+func TestDocumentWithOptions_Compat(t *testing.T) {
+	before, after := providerChangelog()
+	after.ResourcesMap["foo_bar"].Schema["rule"].MaxItems = 2
+	baseline, _ := marshalSchemaJSON(providerSchemas(before, Options{}))
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/out/schema.json", baseline, 0644)
+
+	out := bytes.Buffer{}
+	errs := DocumentWithOptions(after, Options{
+		RootDir:    "/out",
+		Compat:     "/out/schema.json",
+		FileSystem: fs,
+		Out:        &out,
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "[6] breaking change(s)") {
+		t.Fatalf("DocumentWithOptions did not return the correct errors. Expected 6 breaking changes, got: %v", errs)
+	}
+	for _, expected := range []string{
+		"foo_bar: non-breaking: Resource `foo_bar` is deprecated. Use foo_new. [deprecated]\n",
+		"foo_bar.rule: breaking: Block `rule` now allows at most `2` items, down from `3`. [max-items-tightened]\n",
+		"foo_bar.mode: breaking: Attribute `mode` is now required. [made-required]\n",
+		"foo_bar.name: breaking: Attribute `name` now forces a new resource when changed. [made-force-new]\n",
+		"foo_bar.size: non-breaking: Attribute `size` default changed from `1` to `\"2\"`. [default-changed]\n",
+		"foo_new: non-breaking: Resource `foo_new` was added. [added]\n",
+		"foo_baz: breaking: Data Source `foo_baz` was removed. [removed]\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf(
+				"The compatibility report did not return the correct output. "+
+					"Expected [%s] in output:\n%s",
+				expected,
+				out.String(),
+			)
+		}
+	}
+
+	fs.WriteFile("/out/allow.json", []byte(`{"allow": [
+		{"kind": "removed", "object_type": "data_source", "object": "foo_baz", "justification": "Replaced by foo_new."},
+		{"kind": "attribute-renamed", "object_type": "resource", "object": "foo_bar", "attribute": "rule.priority", "justification": "v2"},
+		{"kind": "made-required", "object_type": "resource", "object": "foo_bar", "attribute": "mode", "justification": "v2"},
+		{"kind": "made-force-new", "object_type": "resource", "object": "foo_bar", "attribute": "name", "justification": "v2"},
+		{"kind": "type-changed", "object_type": "resource", "object": "foo_bar", "attribute": "size", "justification": "v2"},
+		{"kind": "max-items-tightened", "object_type": "resource", "object": "foo_bar", "attribute": "rule", "justification": "v2"},
+		{"kind": "removed", "object_type": "resource", "object": "foo_qux", "justification": "v2"}
+	]}`), 0644)
+	out.Reset()
+	errs = DocumentWithOptions(after, Options{
+		RootDir:     "/out",
+		Compat:      "/out/schema.json",
+		CompatAllow: "/out/allow.json",
+		FileSystem:  fs,
+		Out:         &out,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v\n%s", errs, out.String())
+	}
+	for _, expected := range []string{
+		"foo_baz: allowed: Data Source `foo_baz` was removed. [removed] Justification: Replaced by foo_new.\n",
+		"Unused allow file entry [removed resource foo_qux ]. It can be removed.\n",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf(
+				"The compatibility report did not return the correct output. "+
+					"Expected [%s] in output:\n%s",
+				expected,
+				out.String(),
+			)
+		}
+	}
+}
+
+// Ensures allow file entries without a justification are rejected
+func TestDocumentWithOptions_CompatAllowJustification(t *testing.T) {
+	baseline, _ := marshalSchemaJSON(providerSchemas(providerFoo(), Options{}))
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/out/schema.json", baseline, 0644)
+	fs.WriteFile("/out/allow.json", []byte(`{"allow": [
+		{"kind": "removed", "object_type": "data_source", "object": "foo_baz"}
+	]}`), 0644)
+
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:     "/out",
+		Compat:      "/out/schema.json",
+		CompatAllow: "/out/allow.json",
+		FileSystem:  fs,
+		Out:         &bytes.Buffer{},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "has no justification") {
+		t.Fatalf("DocumentWithOptions did not reject the allow file entry without a justification: %v", errs)
+	}
+}
+
+// Ensures required attributes are only breaking when added to blocks that
+// every configuration already contains
+func TestDocumentWithOptions_CompatAddedRequired(t *testing.T) {
+	cases := []struct {
+		name     string
+		before   func(*schema.Resource)
+		after    func(*schema.Resource)
+		breaking int
+	}{
+		{
+			name: "optional block added",
+			before: func(resource *schema.Resource) {
+				delete(resource.Schema, "rule")
+			},
+			after: func(resource *schema.Resource) {
+				resource.Schema["rule"].MinItems = 0
+			},
+			breaking: 0,
+		},
+		{
+			name: "added to optional block",
+			before: func(resource *schema.Resource) {
+				resource.Schema["rule"].MinItems = 0
+				delete(resource.Schema["rule"].Elem.(*schema.Resource).Schema, "priority")
+			},
+			after: func(resource *schema.Resource) {
+				resource.Schema["rule"].MinItems = 0
+			},
+			breaking: 0,
+		},
+		{
+			name: "added to required block",
+			before: func(resource *schema.Resource) {
+				delete(resource.Schema["rule"].Elem.(*schema.Resource).Schema, "priority")
+			},
+			after:    func(resource *schema.Resource) {},
+			breaking: 1,
+		},
+	}
+
+	for _, c := range cases {
+		before := providerFoo()
+		c.before(before.ResourcesMap["foo_bar"])
+		after := providerFoo()
+		c.after(after.ResourcesMap["foo_bar"])
+		baseline, _ := marshalSchemaJSON(providerSchemas(before, Options{}))
+		fs := NewMemoryFileSystem()
+		fs.WriteFile("/out/schema.json", baseline, 0644)
+
+		out := bytes.Buffer{}
+		errs := DocumentWithOptions(after, Options{
+			RootDir:    "/out",
+			Compat:     "/out/schema.json",
+			FileSystem: fs,
+			Out:        &out,
+		})
+		if c.breaking == 0 && len(errs) != 0 {
+			t.Fatalf("DocumentWithOptions returned errors for [%s]: %v\n%s", c.name, errs, out.String())
+		}
+		if c.breaking != 0 && (len(errs) != 1 || !strings.Contains(errs[0].Error(), fmt.Sprintf("[%d] breaking change(s)", c.breaking))) {
+			t.Fatalf("DocumentWithOptions did not return the correct errors for [%s]. Expected %d breaking changes, got: %v", c.name, c.breaking, errs)
+		}
+		if !strings.Contains(out.String(), "foo_bar.rule.priority: ") {
+			t.Fatalf("The compatibility report for [%s] did not report rule.priority:\n%s", c.name, out.String())
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package autodoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// NOTE(ALL): If you make modifications to the built-in templates, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Built-in template set by template association (without the template
+// extension). A built-in template is used whenever the templates directory
+// does not contain a template of the same name.
+var builtinTemplates = map[string]string{
+	mkdocsYmlTemplate:        defaultMkdocsYml,
+	godocMdTemplate:          defaultGodocMd,
+	goPackageMdTemplate:      goPackageMd,
+	providerMdTemplate:       defaultIndexMd,
+	resourceMdTemplate:       defaultSchemaMd,
+	dataSourceMdTemplate:     defaultSchemaMd,
+	argumentPartialTemplate:  argumentPartial,
+	blocksPartialTemplate:    blocksPartial,
+	argumentsPartialTemplate: argumentsPartial,
+	examplePartialTemplate:   examplePartial,
+	registryIndexMdTemplate:  registryIndexMd,
+	registrySchemaMdTemplate: registrySchemaMd,
+	changelogMdTemplate:      changelogMd,
+	hugoTomlTemplate:         hugoToml,
+	sidebarsJsTemplate:       sidebarsJs,
+	bareIndexMdTemplate:      bareIndexMd,
+}
+
+// -----------------------------------------------------------------------------
+// Built-in Template Utility Functions
+// -----------------------------------------------------------------------------
+
+// dumpTemplates writes the built-in templates to the templates directory so
+// they can be customized. Existing templates are never over-written; they are
+// reported as skipped. Each written or skipped file is reported to the
+// options' output. Returns a list of errors. If this list is empty, all
+// templates were written or skipped.
+func dumpTemplates(opts Options) []error {
+	errors := []error{}
+
+	if mkdirErr := os.MkdirAll(opts.TemplatesDir, 0775); mkdirErr != nil {
+		errors = append(errors, mkdirErr)
+		return errors
+	}
+
+	names := []string{}
+	for name := range builtinTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(opts.TemplatesDir, name+opts.TemplateExt)
+		if _, statErr := os.Stat(path); statErr == nil {
+			fmt.Fprintf(opts.Out, "Skipped: %s (already exists)\n", path)
+			continue
+		}
+		body := builtinTemplateBody(builtinTemplates[name], opts)
+		if writeErr := ioutil.WriteFile(path, []byte(body), 0664); writeErr != nil {
+			errors = append(errors, fmt.Errorf(
+				"Cannot dump template [%s]. Error: [%s]",
+				path,
+				writeErr.Error(),
+			))
+			continue
+		}
+		fmt.Fprintf(opts.Out, "Wrote: %s\n", path)
+	}
+	return errors
+}
+
+// -----------------------------------------------------------------------------
+// Built-in Templates
+// -----------------------------------------------------------------------------
+
+// Body of the default mkdocs.yml template
+const defaultMkdocsYml = `site_name: {{.ProviderName}}
+docs_dir: {{.DocsDir}}
+
+nav:
+  - Home: 'index.md'
+{{- if .Guides}}
+  - Guides:
+{{- range .Guides}}
+    - {{printf "%q" .Name}}: '{{.Path}}'
+{{- end}}
+{{- end}}
+{{- if .Resources}}
+  - Resources:
+{{- if .Categorized}}
+{{- range .Categories}}
+{{- if .Resources}}
+    - {{printf "%q" .Name}}:
+{{- range .Resources}}
+      - {{.Name}}: '{{.Path}}'
+{{- end}}
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .Resources}}
+    - {{.}}: 'resources/{{.}}.md'
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .DataSources}}
+  - Data Sources:
+{{- if .Categorized}}
+{{- range .Categories}}
+{{- if .DataSources}}
+    - {{printf "%q" .Name}}:
+{{- range .DataSources}}
+      - {{.Name}}: '{{.Path}}'
+{{- end}}
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .DataSources}}
+    - {{.}}: 'datasources/{{.}}.md'
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .GoPackages}}
+  - Go API:
+    - Overview: 'godoc.md'
+{{- range .GoPackages}}
+    - {{printf "%q" .Name}}: '{{.Path}}'
+{{- end}}
+{{- else}}
+  - Godoc: 'godoc.md'
+{{- end}}
+{{- if .Changelog}}
+  - Changelog: '{{.Changelog}}'
+{{- end}}
+
+theme:
+  name: material
+
+markdown_extensions:
+  - admonition
+  - codehilite
+  - toc:
+      permalink: true
+`
+
+// Body of the default godoc.md template, the index of the Go API reference
+const defaultGodocMd = `# Go API Reference
+
+{{if .GoPackages}}{{range .GoPackages}}* [` + "`{{.Name}}`" + `]({{.Path}}){{if .Summary}} - {{.Summary}}{{end}}
+{{end}}{{else}}No Go packages are documented. List their directories with the
+` + "`-go-packages`" + ` argument.
+{{end}}`
+
+// Body of the default Go API reference page template of a Go package. The
+// "autodoc.godecl" template it defines renders a declaration as a Go code
+// block followed by its documentation.
+const goPackageMd = `{{- define "autodoc.godecl"}}
+` + "```go" + `
+{{.Decl}}
+` + "```" + `
+{{if .Doc}}
+{{.Doc}}{{end}}
+{{- end -}}
+{{.FrontMatter}}# package {{.Name}}
+
+` + "```go" + `
+import "{{.ImportPath}}"
+` + "```" + `
+{{if .Doc}}
+{{.Doc}}{{end}}
+{{- if .Constants}}
+## Constants
+{{range .Constants}}{{template "autodoc.godecl" .}}{{end}}{{end}}
+{{- if .Variables}}
+## Variables
+{{range .Variables}}{{template "autodoc.godecl" .}}{{end}}{{end}}
+{{- if .Functions}}
+## Functions
+{{range .Functions}}
+<a id="{{.Anchor}}"></a>
+### func {{.Name}}
+{{template "autodoc.godecl" .}}{{end}}{{end}}
+{{- if .Types}}
+## Types
+{{range .Types}}
+<a id="{{.Anchor}}"></a>
+### type {{.Name}}
+{{template "autodoc.godecl" .}}{{range .Constants}}{{template "autodoc.godecl" .}}{{end}}{{range .Variables}}{{template "autodoc.godecl" .}}{{end}}{{range .Functions}}
+<a id="{{.Anchor}}"></a>
+#### func {{.Name}}
+{{template "autodoc.godecl" .}}{{end}}{{range .Methods}}
+<a id="{{.Anchor}}"></a>
+#### func ({{.Recv}}) {{.Name}}
+{{template "autodoc.godecl" .}}{{end}}{{end}}{{end}}`
+
+// Body of the default provider page template
+const defaultIndexMd = `{{.FrontMatter}}# {{.Name}}
+
+{{if .Meta.Summary}}{{.Meta.Summary}}
+
+{{end -}}
+## Example Usage
+
+{{template "_example.template" .}}
+
+## Argument Reference
+
+{{template "_arguments.template" .}}{{template "_blocks.template" .}}`
+
+// Body of the default resource and data source page template
+const defaultSchemaMd = `{{.FrontMatter}}# {{.Name}}
+
+{{if .Meta.Summary}}{{.Meta.Summary}}
+
+{{end -}}
+{{if .Meta.Deprecated}}{{admonition "warning" (printf "**Deprecated:** %s" .Meta.Deprecated)}}
+
+{{end -}}
+{{if .Meta.Uncreatable}}{{admonition "note" "This resource cannot be created. It must be imported."}}
+
+{{end -}}
+{{if .Meta.Immutable}}{{admonition "note" "This resource cannot be updated. Any change re-creates it."}}
+
+{{end -}}
+{{if .Meta.Undeletable}}{{admonition "note" "This resource cannot be deleted. It is only removed from the state."}}
+
+{{end -}}
+## Example Usage
+
+{{template "_example.template" .}}
+
+## Argument Reference
+
+{{template "_arguments.template" .}}
+## Attributes Reference
+
+The following attributes are exported:
+
+{{range .Attributes}}* <a id="{{.AttributeAnchor}}"></a>` + "`{{.Name}}`" + ` - {{if .Sensitive}}(Sensitive) {{end}}{{.Type}}.{{if .Description}} {{.Description}}{{end}}{{if .Deprecated}} **Deprecated:** {{.Deprecated}}{{end}}
+{{end}}{{template "_blocks.template" .}}
+{{- if .Timeouts}}
+## Timeouts
+
+The ` + "`timeouts`" + ` block allows you to specify [timeouts](https://www.terraform.io/docs/configuration/resources.html#operation-timeouts) for certain operations:
+
+{{range .Timeouts}}* ` + "`{{.Name}}`" + ` - (Defaults to {{.Default}}) {{.Description}}
+{{end}}{{end}}
+{{- if .Importable}}
+## Import
+
+` + "`{{.Name}}`" + ` can be imported using {{if .ImportIDFormat}}an ID of the form ` + "`{{.ImportIDFormat}}`" + `{{else}}its ID{{end}}:
+
+` + "```shell" + `
+terraform import {{.Name}}.example {{if .ImportIDFormat}}{{.ImportIDFormat}}{{else}}<id>{{end}}
+` + "```" + `
+{{end}}
+{{- if .ReferencedBy}}
+## Referenced By
+
+{{range .ReferencedBy}}* [` + "`{{.Name}}`" + `]({{.Path}})
+{{end}}{{end}}`
+
+// Body of the built-in argument partial. It renders one argument as a list
+// item followed by its default, item counts, and the constraints between it
+// and other arguments. The "autodoc.constraints" template it defines may be
+// used on its own to append the constraints to a custom list item.
+const argumentPartial = `{{- define "autodoc.names"}}
+{{- range $idx, $name := .}}{{if $idx}}, {{end}}` + "`{{$name}}`" + `{{end}}
+{{- end}}
+{{- define "autodoc.constraints"}}
+{{- if .Default}} Defaults to ` + "`{{.Default}}`" + `.{{end}}
+{{- if and .MinItems .MaxItems}} Between {{.MinItems}} and {{.MaxItems}} items.
+{{- else if .MinItems}} At least {{.MinItems}} items.
+{{- else if .MaxItems}} At most {{.MaxItems}} items.{{end}}
+{{- if .ConflictsWith}} Conflicts with {{template "autodoc.names" .ConflictsWith}}.{{end}}
+{{- if .ExactlyOneOf}} Exactly one of {{template "autodoc.names" .ExactlyOneOf}} must be set.{{end}}
+{{- if .AtLeastOneOf}} At least one of {{template "autodoc.names" .AtLeastOneOf}} must be set.{{end}}
+{{- if .RequiredWith}} Requires {{template "autodoc.names" .RequiredWith}}.{{end}}
+{{- if .ComputedWhen}} Re-computed when {{template "autodoc.names" .ComputedWhen}} change.{{end}}
+{{- if eq .ConfigMode "attribute"}} Set with attribute syntax (` + "`{{.Name}} = [...]`" + `) instead of blocks.{{end}}
+{{- if .Deprecated}} **Deprecated:** {{.Deprecated}}{{end}}
+{{- end -}}
+* ` + "`{{.Name}}`" + ` - ({{if .Optional}}Optional{{else}}Required{{end}}{{if .ForceNew}}, ForceNew{{end}}{{if .Sensitive}}, Sensitive{{end}}) {{.Type}}.{{if .Description}} {{.Description}}{{end}}{{template "autodoc.constraints" .}}
+`
+
+// Body of the built-in arguments partial. It renders the argument list of a
+// provider, resource, or data source, one _argument.template per argument.
+const argumentsPartial = `{{if .Arguments}}The following arguments are supported:
+
+{{range .Arguments}}{{template "_argument.template" .}}{{end}}{{else}}This {{if eq .SchemaType .Constants.TypeProvider}}provider{{else if eq .SchemaType .Constants.TypeResource}}resource{{else}}data source{{end}} has no arguments.
+{{end}}`
+
+// Body of the built-in example partial. It renders the example HCL
+// configuration block as a fenced code block.
+const examplePartial = "```" + `hcl
+{{.ExampleHCL}}` + "```"
+
+// Body of the built-in nested blocks partial. Each block gets its own section
+// with an anchor so the block's argument/attribute type can link to it.
+// Blocks are rendered depth first.
+const blocksPartial = `{{- define "autodoc.block"}}
+<a id="{{.Anchor}}"></a>
+### ` + "`{{.Path}}`" + ` block
+
+{{if .Description}}{{.Description}}
+
+{{end -}}
+Nesting mode: ` + "`{{.NestingMode}}`" + `{{if .MinItems}}, minimum items: {{.MinItems}}{{end}}{{if .MaxItems}}, maximum items: {{.MaxItems}}{{end}}
+{{if .Arguments}}
+#### Arguments
+
+{{range .Arguments}}{{template "_argument.template" .}}{{end}}{{end}}{{if .Attributes}}
+#### Attributes
+
+{{range .Attributes}}* <a id="{{.AttributeAnchor}}"></a>` + "`{{.Name}}`" + ` - {{if .Sensitive}}(Sensitive) {{end}}{{.Type}}.{{if .Description}} {{.Description}}{{end}}{{if .Deprecated}} **Deprecated:** {{.Deprecated}}{{end}}
+{{end}}{{end}}{{range .Blocks}}{{template "autodoc.block" .}}{{end}}
+{{- end}}
+{{- range .Blocks}}{{template "autodoc.block" .}}{{end -}}
+`
@@ -0,0 +1,192 @@
+package autodoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// Built-in Templates
+// -----------------------------------------------------------------------------
+
+// Ensures the built-in templates are used when the templates directory is
+// missing, and user templates over-ride them one file at a time
+func TestDocumentWithOptions_DefaultTemplates(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	templatesDir := writeTemplates(t, map[string]string{
+		"godoc.md.template": "custom godoc\n",
+	})
+	defer os.RemoveAll(templatesDir)
+	errs := DocumentWithOptions(providerFoo(), Options{
+		ProviderName: "Foo",
+		RootDir:      "/out",
+		TemplatesDir: templatesDir,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	godocDoc, _ := fs.ReadFile("/out/docs/godoc.md")
+	if string(godocDoc) != "custom godoc\n" {
+		t.Fatalf(
+			"DocumentWithOptions did not use the user template. Got [%q].",
+			string(godocDoc),
+		)
+	}
+
+	mkdocsYml, _ := fs.ReadFile("/out/mkdocs.yml")
+	for _, expected := range []string{
+		"site_name: Foo\ndocs_dir: docs\n",
+		"  - Resources:\n    - foo_bar: 'resources/foo_bar.md'\n",
+		"  - Data Sources:\n    - foo_baz: 'datasources/foo_baz.md'\n",
+	} {
+		if !strings.Contains(string(mkdocsYml), expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Expected [%s] in output:\n%s",
+				expected,
+				string(mkdocsYml),
+			)
+		}
+	}
+
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/foo_bar.md")
+	if !strings.HasPrefix(string(resourceDoc), "# foo_bar\n") {
+		t.Fatalf(
+			"DocumentWithOptions did not use the default template. Got:\n%s",
+			string(resourceDoc),
+		)
+	}
+}
+
+// Ensures dumped templates are written with the template extension, existing
+// templates are skipped, and the dumped set parses
+func TestDumpTemplates(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"index.md.tmpl": "custom index\n",
+	})
+	defer os.RemoveAll(dir)
+	out := bytes.Buffer{}
+	opts := Options{
+		TemplatesDir: dir,
+		TemplateExt:  ".tmpl",
+		Out:          &out,
+	}
+	if errs := dumpTemplates(opts); len(errs) != 0 {
+		t.Fatalf("dumpTemplates returned errors: %v", errs)
+	}
+
+	for name := range builtinTemplates {
+		path := filepath.Join(dir, name+".tmpl")
+		if _, err := ioutil.ReadFile(path); err != nil {
+			t.Fatalf("dumpTemplates did not write [%s]", path)
+		}
+	}
+	index, _ := ioutil.ReadFile(filepath.Join(dir, "index.md.tmpl"))
+	if string(index) != "custom index\n" {
+		t.Fatalf("dumpTemplates over-wrote an existing template")
+	}
+	if !strings.Contains(out.String(), "Skipped: "+filepath.Join(dir, "index.md.tmpl")) {
+		t.Fatalf(
+			"dumpTemplates did not report the skipped template. Got:\n%s",
+			out.String(),
+		)
+	}
+
+	resource, _ := ioutil.ReadFile(filepath.Join(dir, "resource.md.tmpl"))
+	if !strings.Contains(string(resource), `{{template "_blocks.tmpl" .}}`) {
+		t.Fatalf(
+			"dumpTemplates did not rewrite the partial template extension. "+
+				"Got:\n%s",
+			string(resource),
+		)
+	}
+	if _, err := parseTemplates(opts); err != nil {
+		t.Fatalf("Dumped templates do not parse: [%s]", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Template Overrides and Partials
+// -----------------------------------------------------------------------------
+
+// Ensures a resource with its own template is rendered with it, other pages
+// fall back to the template of their type, and user partials are found in
+// sub directories
+func TestDocumentWithOptions_TemplateOverrides(t *testing.T) {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_qux"] = resourceFoo()
+
+	fs := NewMemoryFileSystem()
+	templatesDir := writeTemplates(t, map[string]string{
+		"resources/foo_bar.md.template":     "# Special {{.Name}}\n{{template \"_note.template\" .}}",
+		"datasources/foo_bar.md.template":   "# Not a data source\n",
+		"_partials/_note.template":          "Note for {{.Name}}.\n",
+		"_partials/_example.template":       "EXAMPLE\n",
+		"resources/nested/foo_qux.template": "# Not matched {{.Name}}\n",
+	})
+	defer os.RemoveAll(templatesDir)
+	errs := DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		TemplatesDir: templatesDir,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	for path, expected := range map[string]string{
+		"/out/docs/resources/foo_bar.md":   "# Special foo_bar\nNote for foo_bar.\n",
+		"/out/docs/resources/foo_qux.md":   "# foo_qux\n",
+		"/out/docs/datasources/foo_baz.md": "# foo_baz\n",
+	} {
+		content, _ := fs.ReadFile(path)
+		if !strings.HasPrefix(string(content), expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not use the correct template for "+
+					"[%s]. Expected [%s], got:\n%s",
+				path,
+				expected,
+				string(content),
+			)
+		}
+	}
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/foo_qux.md")
+	if !strings.Contains(string(resourceDoc), "## Example Usage\n\nEXAMPLE\n") {
+		t.Fatalf(
+			"DocumentWithOptions did not use the user partial. Got:\n%s",
+			string(resourceDoc),
+		)
+	}
+}
+
+// Ensures a template that includes a partial that does not exist is reported
+// by name before any file is rendered
+func TestParseTemplates_MissingPartial(t *testing.T) {
+	templatesDir := writeTemplates(t, map[string]string{
+		"resource.md.template": "# {{.Name}}\n{{if .Arguments}}{{template \"_exmaple.template\" .}}{{end}}",
+	})
+	defer os.RemoveAll(templatesDir)
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:      "/out",
+		TemplatesDir: templatesDir,
+		FileSystem:   NewMemoryFileSystem(),
+	})
+	expected := "Template [resource.md.template] includes the template " +
+		"[_exmaple.template], which does not exist. Partials: " +
+		"[_argument.template, _arguments.template, _blocks.template, " +
+		"_example.template]."
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Expected "+
+				"[%s], got %v.",
+			expected,
+			errs,
+		)
+	}
+}
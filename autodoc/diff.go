@@ -0,0 +1,230 @@
+package autodoc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Number of unchanged lines shown around each change in a unified diff
+const diffContextLines = 3
+
+// A single line of an edit script
+type diffLine struct {
+	// ' ' for a line common to both inputs, '-' for a line only in the old
+	// input, '+' for a line only in the new input
+	op byte
+	// The line's text, without its trailing newline
+	text string
+}
+
+// unifiedDiff returns the unified diff to transform the old text into the
+// new text, labelled with the supplied file names. The empty string is
+// returned if the texts are identical.
+func unifiedDiff(oldName string, newName string, oldText []byte, newText []byte) string {
+	if bytes.Equal(oldText, newText) {
+		return ""
+	}
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	out := strings.Builder{}
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Group changes into hunks. A hunk ends once more than twice the number
+	// of context lines separate it from the next change.
+	for start := 0; start < len(lines); {
+		// find the next change
+		for start < len(lines) && lines[start].op == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+		first := start - diffContextLines
+		if first < 0 {
+			first = 0
+		}
+		end := start
+		for end < len(lines) {
+			if lines[end].op != ' ' {
+				end++
+				continue
+			}
+			// count the unchanged lines until the next change
+			run := end
+			for run < len(lines) && lines[run].op == ' ' {
+				run++
+			}
+			if run == len(lines) || run-end > 2*diffContextLines {
+				break
+			}
+			end = run
+		}
+		last := end + diffContextLines
+		if last > len(lines) {
+			last = len(lines)
+		}
+		writeHunk(&out, lines, first, last)
+		start = last
+	}
+	return out.String()
+}
+
+// writeHunk writes the hunk header and lines for lines[first:last]
+func writeHunk(out *strings.Builder, lines []diffLine, first int, last int) {
+	// line numbers of the hunk start are 1-based and count only the lines
+	// present in each input
+	oldStart, newStart := 1, 1
+	for _, line := range lines[:first] {
+		if line.op != '+' {
+			oldStart++
+		}
+		if line.op != '-' {
+			newStart++
+		}
+	}
+	oldCount, newCount := 0, 0
+	for _, line := range lines[first:last] {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(
+		out,
+		"@@ -%s +%s @@\n",
+		hunkRange(oldStart, oldCount),
+		hunkRange(newStart, newCount),
+	)
+	for _, line := range lines[first:last] {
+		fmt.Fprintf(out, "%c%s\n", line.op, line.text)
+	}
+}
+
+// hunkRange formats the start,count range of a hunk header. An empty range
+// refers to the line before it.
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// diffLines computes the edit script between the old and new lines from
+// their longest common subsequence. The common prefix and suffix are trimmed
+// first, and the rest is diffed in linear space, so large files with few
+// changes stay cheap to compare.
+func diffLines(oldLines []string, newLines []string) []diffLine {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	lines := []diffLine{}
+	for _, line := range oldLines[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	lines = diffMiddle(lines, oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	return lines
+}
+
+// diffMiddle appends the edit script between the old and new lines to lines
+// with Hirschberg's algorithm: the old lines are split in half, the new
+// lines are split where the longest common subsequences of both halves add
+// up to the longest, and each half is diffed recursively. Only two rows of
+// subsequence lengths are kept at a time.
+func diffMiddle(lines []diffLine, oldLines []string, newLines []string) []diffLine {
+	switch {
+	case len(oldLines) == 0:
+		for _, line := range newLines {
+			lines = append(lines, diffLine{'+', line})
+		}
+		return lines
+	case len(newLines) == 0:
+		for _, line := range oldLines {
+			lines = append(lines, diffLine{'-', line})
+		}
+		return lines
+	case len(oldLines) == 1:
+		for j, line := range newLines {
+			if line != oldLines[0] {
+				continue
+			}
+			for _, added := range newLines[:j] {
+				lines = append(lines, diffLine{'+', added})
+			}
+			lines = append(lines, diffLine{' ', line})
+			for _, added := range newLines[j+1:] {
+				lines = append(lines, diffLine{'+', added})
+			}
+			return lines
+		}
+		lines = append(lines, diffLine{'-', oldLines[0]})
+		for _, line := range newLines {
+			lines = append(lines, diffLine{'+', line})
+		}
+		return lines
+	}
+
+	mid := len(oldLines) / 2
+	forward := lcsLengths(oldLines[:mid], newLines, false)
+	backward := lcsLengths(oldLines[mid:], newLines, true)
+	split, best := 0, -1
+	for k := 0; k <= len(newLines); k++ {
+		if length := forward[k] + backward[len(newLines)-k]; length > best {
+			split, best = k, length
+		}
+	}
+	lines = diffMiddle(lines, oldLines[:mid], newLines[:split])
+	return diffMiddle(lines, oldLines[mid:], newLines[split:])
+}
+
+// lcsLengths returns, for each k, the length of the longest common
+// subsequence of the old lines and the first k new lines. If reverse is set,
+// both inputs are read from the end, so k counts the last new lines.
+func lcsLengths(oldLines []string, newLines []string, reverse bool) []int {
+	at := func(lines []string, idx int) string {
+		if reverse {
+			return lines[len(lines)-1-idx]
+		}
+		return lines[idx]
+	}
+	prev := make([]int, len(newLines)+1)
+	curr := make([]int, len(newLines)+1)
+	for i := range oldLines {
+		for j := range newLines {
+			switch {
+			case at(oldLines, i) == at(newLines, j):
+				curr[j+1] = prev[j] + 1
+			case prev[j+1] >= curr[j]:
+				curr[j+1] = prev[j+1]
+			default:
+				curr[j+1] = curr[j]
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev
+}
+
+// splitLines splits text into lines, dropping the trailing newline of the
+// last line
+func splitLines(text []byte) []string {
+	if len(text) == 0 {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")
+}
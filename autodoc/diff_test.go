@@ -0,0 +1,90 @@
+package autodoc
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// unifiedDiff
+// -----------------------------------------------------------------------------
+
+// Ensures identical texts produce no diff
+func TestUnifiedDiff_Identical(t *testing.T) {
+	text := []byte("a\nb\n")
+	if diff := unifiedDiff("a", "b", text, text); diff != "" {
+		t.Fatalf(
+			"unifiedDiff did not return the correct output. Expected no "+
+				"diff, got:\n%s",
+			diff,
+		)
+	}
+}
+
+// Ensures changes far apart are split into separate hunks with context
+func TestUnifiedDiff_Hunks(t *testing.T) {
+	oldText := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	newText := []byte("1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n")
+	expected := "--- old\n+++ new\n" +
+		"@@ -1,5 +1,5 @@\n 1\n-2\n+TWO\n 3\n 4\n 5\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	actual := unifiedDiff("old", "new", oldText, newText)
+	if actual != expected {
+		t.Fatalf(
+			"unifiedDiff did not return the correct output. Expected:\n%s\n"+
+				"got:\n%s",
+			expected,
+			actual,
+		)
+	}
+}
+
+// Ensures the edit script transforms the old lines into the new lines and
+// keeps a longest common subsequence, including in large inputs
+func TestDiffLines(t *testing.T) {
+	large := []string{}
+	for i := 0; i < 20000; i++ {
+		large = append(large, strconv.Itoa(i))
+	}
+	changed := append(append([]string{}, large[:10000]...), "x")
+	changed = append(changed, large[10001:]...)
+
+	for _, c := range []struct {
+		oldLines []string
+		newLines []string
+		common   int
+	}{
+		{[]string{"a", "b", "c", "a", "b", "b", "a"}, []string{"c", "b", "a", "b", "a", "c"}, 4},
+		{[]string{"a", "b"}, []string{}, 0},
+		{[]string{}, []string{"a"}, 0},
+		{[]string{"a", "x", "b"}, []string{"a", "y", "z", "b"}, 2},
+		{large, changed, 19999},
+	} {
+		oldLines, newLines := []string{}, []string{}
+		common := 0
+		for _, line := range diffLines(c.oldLines, c.newLines) {
+			if line.op != '+' {
+				oldLines = append(oldLines, line.text)
+			}
+			if line.op != '-' {
+				newLines = append(newLines, line.text)
+			}
+			if line.op == ' ' {
+				common++
+			}
+		}
+		if strings.Join(oldLines, "\n") != strings.Join(c.oldLines, "\n") ||
+			strings.Join(newLines, "\n") != strings.Join(c.newLines, "\n") ||
+			common != c.common {
+			t.Fatalf(
+				"diffLines did not return the correct output for %v => %v. "+
+					"Expected [%d] common lines, got [%d].",
+				c.oldLines,
+				c.newLines,
+				c.common,
+				common,
+			)
+		}
+	}
+}
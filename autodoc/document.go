@@ -1,11 +1,14 @@
 package autodoc
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -25,22 +28,55 @@ type goroutineBase struct {
 	template *template.Template
 	// Name of the template to use to generate the output file
 	templateName string
-	// Bidirectional error channel for communication to main goroutine. This
-	// should return nil if no errors are encountered when generating the
-	// documentation.  Otherwise, the main goroutine will receive an error
-	// from this channel.
-	errChan chan error
+	// Bidirectional output channel for communication to main goroutine. The
+	// rendered output file is sent over this channel. Its error should be
+	// nil if no errors are encountered when generating the documentation.
+	// Otherwise, the main goroutine will receive the error from this channel.
+	outChan chan renderedFile
 }
 
-// Represents the mkdocs.yml document. This information is passed to the
-// goroutine generating mkdocs.yml
-type mkdocsYmlDoc struct {
+// The rendered contents of an output file. The main goroutine either writes
+// the contents to the output filesystem or compares them to the existing
+// file, depending on the options.
+type renderedFile struct {
+	// Path to the output file
+	path string
+	// Rendered contents of the file
+	content []byte
+	// Error encountered while rendering the file, nil on success
+	err error
+}
+
+// Represents a site configuration file (ie: mkdocs.yml). This information is
+// passed to the goroutine generating the file.
+type siteFileDoc struct {
 	// Contains base goroutine information
 	goroutineBase
-	// Includes a reference to the Terraform provider
-	provider *schema.Provider
-	// Includes a reference to the command line arguments
-	args parsedArgs
+	// Template data of the site configuration files
+	data siteData
+	// Merges the rendered file into the existing file. nil if the rendered
+	// file replaces the existing file.
+	merge func(existing []byte, generated []byte) []byte
+	// The output filesystem the existing file is read from
+	fileSystem FileSystem
+}
+
+// Represents a guide page. This information is passed to the goroutine
+// generating the guide.
+type guideDoc struct {
+	// Contains base goroutine information
+	goroutineBase
+	// Template data of the guide
+	data guideDocData
+}
+
+// Represents the Go API reference page of a Go package. This information is
+// passed to the goroutine generating the page.
+type goPackageDoc struct {
+	// Contains base goroutine information
+	goroutineBase
+	// Template data of the Go package
+	data goPackageDocData
 }
 
 // Represents a markdown schema document. This information is passed to the
@@ -55,6 +91,35 @@ type schemaDoc struct {
 	name string
 	// Include a reference to the schema to be documented
 	schema map[string]*schema.Schema
+	// Include a reference to the resource being documented. This is nil for
+	// the provider.
+	resource *schema.Resource
+	// Name of the provider being documented
+	providerName string
+	// Type name of the provider in the config
+	providerType string
+	// Names of the provider resources and data sources. Only set for the
+	// provider.
+	resources   []string
+	dataSources []string
+	// Resources and data sources grouped by category. Only set for the
+	// provider.
+	categories  []siteCategory
+	categorized bool
+	// Pages of the guides. Only set for the provider.
+	guides []sitePage
+	// Pages of the Go API reference. Only set for the provider.
+	goPackages []sitePage
+	// Returns the front matter of the page. nil if the page has no front
+	// matter.
+	frontMatter func(data schemaDocData) string
+	// Reference to the provider, to resolve the references in descriptions
+	provider *schema.Provider
+	// Site generator that lays out the pages references link to
+	site siteGenerator
+	// The provider, resources, and data sources that reference this resource
+	// or data source
+	referencedBy []schemaObject
 }
 
 // -----------------------------------------------------------------------------
@@ -67,172 +132,222 @@ type schemaDoc struct {
 // resource, or a data.
 func generateSchemaDoc(d schemaDoc) {
 	// template data
-	data := schemaDocData{
-		Constants: map[string]interface{}{
-			"TypeProvider":   typeProvider,
-			"TypeResource":   typeResource,
-			"TypeDataSource": typeDataSource,
-		},
-		SchemaType: d.schemaType,
-		Name:       d.name,
-		Meta:       parseMeta(d.schema),
-		Attributes: schemaAttributes(d.schema),
-		Arguments:  schemaArguments(d.schema),
+	data := newSchemaDocData(d.schemaType, d.name, d.resource, d.schema)
+	data.ProviderName = d.providerName
+	data.ProviderType = d.providerType
+	data.Resources = d.resources
+	data.DataSources = d.dataSources
+	data.Categories = d.categories
+	data.Categorized = d.categorized
+	data.Guides = d.guides
+	data.GoPackages = d.goPackages
+	if d.frontMatter != nil {
+		// front matter cannot link, so references are written as their target
+		plain := data
+		plain.Meta.Summary = plainRefs(data.Meta.Summary)
+		data.FrontMatter = d.frontMatter(plain)
 	}
-	// sort argument and attributes list alphabetically for easier reading
-	sort.Slice(data.Arguments, func(i, j int) bool {
-		return data.Arguments[i].Name < data.Arguments[j].Name
-	})
-	sort.Slice(data.Attributes, func(i, j int) bool {
-		return data.Attributes[i].Name < data.Attributes[j].Name
-	})
 
-	// requested template should exist and be defined
-	if d.template.Lookup(d.templateName) == nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [%s]. Template [%s] "+
-				"does not exist or is not defined.",
-			d.outFile,
-			d.templateName,
-		)
+	// metadata tags that cannot be parsed fail the generation, rather than
+	// silently documenting a partial description
+	if tagErr := validateMetaTags(d.name, d.resource, d.schema); tagErr != nil {
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Error: [%s]",
+				d.outFile,
+				tagErr.Error(),
+			),
+		}
 		return
 	}
 
-	// open output file
-	fd, fileErr := openFile(d.goroutineBase)
-	defer fd.Close()
-	if fileErr != nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [%s]. Failed to get file descriptor. "+
-				"Error: [%s]",
-			d.goroutineBase.outFile,
-			fileErr.Error(),
-		)
+	// references to resources or attributes that do not exist fail the
+	// generation, rather than documenting a broken link
+	if refErr := validateRefs(d.provider, d.name, d.resource, d.schema); refErr != nil {
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Error: [%s]",
+				d.outFile,
+				refErr.Error(),
+			),
+		}
 		return
 	}
+	linkDocRefs(d.site, &data)
+	data.ReferencedBy = schemaReferences(d.site, data, d.referencedBy)
 
-	// Execute template with supplied data, dump output to our file descriptor
-	templateErr := d.template.ExecuteTemplate(
-		fd,
-		d.templateName,
-		data,
-	)
-
-	// Signal error back to main goroutine
-	d.errChan <- templateErr
-}
-
-// generateGodocMd generates the wrapper documentation file that serves as a
-// viewport to the godoc.
-func generateGodocMd(d goroutineBase) {
-	// requested template should exist and be defined
-	if d.template.Lookup(d.templateName) == nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [%s]. Template [%s] "+
-				"does not exist or is not defined.",
-			d.outFile,
-			d.templateName,
-		)
+	// the example is generated from the @EXAMPLE values and a malformed
+	// value fails the generation
+	exampleName := d.name
+	if d.schemaType == typeProvider {
+		exampleName = d.providerType
+	}
+	example, exampleErr := exampleHCL(d.schemaType, exampleName, d.schema)
+	if exampleErr != nil {
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Error: [%s]",
+				d.outFile,
+				exampleErr.Error(),
+			),
+		}
 		return
 	}
+	data.ExampleHCL = example
 
-	// open output file
-	fd, fileErr := openFile(d)
-	defer fd.Close()
-	if fileErr != nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [%s]. Failed to get file descriptor. "+
-				"Error: [%s]",
-			d.outFile,
-			fileErr.Error(),
-		)
+	// requested template should exist and be defined
+	if d.template.Lookup(d.templateName) == nil {
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Template [%s] "+
+					"does not exist or is not defined.",
+				d.outFile,
+				d.templateName,
+			),
+		}
 		return
 	}
 
-	// Execute template with supplied data, dump output to our file descriptor
-	templateErr := d.template.ExecuteTemplate(
-		fd,
-		d.templateName,
-		nil,
-	)
-
-	// Signal error back to main goroutine
-	d.errChan <- templateErr
+	// Execute template with supplied data. Signal output back to main
+	// goroutine
+	d.outChan <- renderTemplate(d.goroutineBase, data)
 }
 
-// generateMkdocsYml genreates the mkdocs.yml file which configures the
-// mkdocs build.
-func generateMkdocsYml(d mkdocsYmlDoc) {
-	// template data
-	data := mkdocsYmlData{
-		DocsDir: d.args.docsDir,
-	}
-
+// generateSiteFile generates a site configuration file of the site generator
+// (ie: mkdocs.yml, which configures the mkdocs build). Files with a merge
+// function are merged into the existing file, if there is one.
+func generateSiteFile(d siteFileDoc) {
 	// requested template should exist and be defined
 	if d.template.Lookup(d.templateName) == nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [%s]. Template [%s] "+
-				"does not exist or is not defined.",
-			d.outFile,
-			d.templateName,
-		)
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Template [%s] "+
+					"does not exist or is not defined.",
+				d.outFile,
+				d.templateName,
+			),
+		}
 		return
 	}
 
-	// provider reference should not be nil
-	if d.provider == nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [mkdocs.yml]. Provider reference is nil.",
-		)
+	// Execute template with supplied data
+	rendered := renderTemplate(d.goroutineBase, d.data)
+	if rendered.err != nil || d.merge == nil {
+		d.outChan <- rendered
 		return
 	}
 
-	// get the list of resources, data sources from the provider schema
-	for resourceName, _ := range d.provider.ResourcesMap {
-		data.Resources = append(data.Resources, resourceName)
+	// Merge the rendered file into the existing file, if there is one.
+	// Signal output back to main goroutine
+	existing, readErr := d.fileSystem.ReadFile(d.outFile)
+	switch {
+	case os.IsNotExist(readErr):
+	case readErr != nil:
+		rendered.err = fmt.Errorf(
+			"Cannot generate [%s]. Failed to read file. Error: [%s]",
+			d.outFile,
+			readErr.Error(),
+		)
+	default:
+		rendered.content = d.merge(existing, rendered.content)
 	}
-	for dataSourceName, _ := range d.provider.DataSourcesMap {
-		data.DataSources = append(data.DataSources, dataSourceName)
+	d.outChan <- rendered
+}
+
+// generateGuideDoc generates a guide page from its template in the guides
+// templates directory
+func generateGuideDoc(d guideDoc) {
+	// requested template should exist and be defined
+	if d.template.Lookup(d.templateName) == nil {
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Template [%s] "+
+					"does not exist or is not defined.",
+				d.outFile,
+				d.templateName,
+			),
+		}
+		return
 	}
 
-	// sort the resource, data source list by name for easier reading
-	sort.Slice(data.Resources, func(i, j int) bool {
-		return data.Resources[i] < data.Resources[j]
-	})
-	sort.Slice(data.DataSources, func(i, j int) bool {
-		return data.DataSources[i] < data.DataSources[j]
-	})
+	// Execute template with supplied data. Signal output back to main
+	// goroutine
+	d.outChan <- renderTemplate(d.goroutineBase, d.data)
+}
 
-	// open output file
-	fd, fileErr := openFile(d.goroutineBase)
-	defer fd.Close()
-	if fileErr != nil {
-		d.errChan <- fmt.Errorf(
-			"Cannot generate [mkdocs.yml]. Could not get file descriptor. "+
-				"Error: [%s]",
-			fileErr.Error(),
-		)
+// generateGoPackageDoc generates the Go API reference page of a Go package
+func generateGoPackageDoc(d goPackageDoc) {
+	// requested template should exist and be defined
+	if d.template.Lookup(d.templateName) == nil {
+		d.outChan <- renderedFile{
+			path: d.outFile,
+			err: fmt.Errorf(
+				"Cannot generate [%s]. Template [%s] "+
+					"does not exist or is not defined.",
+				d.outFile,
+				d.templateName,
+			),
+		}
 		return
 	}
 
-	// Execute template with supplied data, dump output to our file descriptor
-	templateErr := d.template.ExecuteTemplate(
-		fd,
-		d.templateName,
-		data,
-	)
-
-	// Signal error back to main goroutine
-	d.errChan <- templateErr
+	// Execute template with supplied data. Signal output back to main
+	// goroutine
+	d.outChan <- renderTemplate(d.goroutineBase, d.data)
 }
 
 // -----------------------------------------------------------------------------
 // Documentation Utility Functions
 // -----------------------------------------------------------------------------
 
+// newSchemaDocData returns the template data of a provider, resource, or data
+// source schema: its metadata, attributes, arguments, blocks, and import and
+// timeout information. The provider and site fields are left empty, and the
+// references in descriptions are not linked.
+func newSchemaDocData(schemaType int, name string, resource *schema.Resource, schemaMap map[string]*schema.Schema) schemaDocData {
+	data := schemaDocData{
+		Constants: map[string]interface{}{
+			"TypeProvider":   typeProvider,
+			"TypeResource":   typeResource,
+			"TypeDataSource": typeDataSource,
+		},
+		SchemaType: schemaType,
+		Name:       name,
+		ShortName:  name,
+		Meta:       parseMeta(schemaType, resource, schemaMap),
+		Attributes: schemaAttributes(schemaMap, ""),
+		Arguments:  schemaArguments(schemaMap, ""),
+		Blocks:     schemaBlocks(schemaMap, ""),
+	}
+	// sort argument and attributes list alphabetically for easier reading
+	sort.Slice(data.Arguments, func(i, j int) bool {
+		return data.Arguments[i].Name < data.Arguments[j].Name
+	})
+	sort.Slice(data.Attributes, func(i, j int) bool {
+		return data.Attributes[i].Name < data.Attributes[j].Name
+	})
+	if schemaType != typeProvider {
+		data.ShortName = shortName(name)
+	}
+	if resource != nil {
+		data.Importable = resource.Importer != nil
+		data.ImportIDFormat = parseImportID(resource, schemaMap)
+		data.Timeouts = schemaTimeouts(resource.Timeouts)
+	}
+	return data
+}
+
 // schemaAttributes scans all the schema attributes and parses them into
-// a list of exported schema attributes
-func schemaAttributes(schemaMap map[string]*schema.Schema) []schemaAttribute {
+// a list of exported schema attributes. parentPath is the path of the nested
+// block the schema map belongs to, or the empty string for the root of the
+// resource.
+func schemaAttributes(schemaMap map[string]*schema.Schema, parentPath string) []schemaAttribute {
 	attrs := []schemaAttribute{}
 	for attrName, attrSchema := range schemaMap {
 		// skip the meta attribute
@@ -241,13 +356,22 @@ func schemaAttributes(schemaMap map[string]*schema.Schema) []schemaAttribute {
 		}
 		// if the attribute is tagged as unexported, do not include it in the
 		// attribute list
-		if strings.Contains(attrSchema.Description, MetaUnexported) {
+		parsed, _ := parseDescription(attrSchema.Description)
+		if parsed.has(MetaUnexported) {
 			continue
 		}
 		attr := schemaAttribute{
-			Name:        attrName,
-			Type:        schemaType(attrSchema),
-			Description: stripMeta(attrSchema.Description),
+			Name:            attrName,
+			Type:            schemaType(attrSchema),
+			Description:     parsed.text,
+			Sensitive:       attrSchema.Sensitive,
+			Deprecated:      attrSchema.Deprecated,
+			AttributeAnchor: attributeAnchor(blockPath(parentPath, attrName)),
+			Custom:          customTags(parsed),
+		}
+		if isBlock(attrSchema) {
+			attr.Anchor = blockAnchor(blockPath(parentPath, attrName))
+			attr.Type = blockType(attrSchema, attr.Anchor)
 		}
 		attrs = append(attrs, attr)
 	}
@@ -255,8 +379,9 @@ func schemaAttributes(schemaMap map[string]*schema.Schema) []schemaAttribute {
 }
 
 // schemaArguments scans all the schema attributes and parses them into
-// a list of schema arguments
-func schemaArguments(schemaMap map[string]*schema.Schema) []schemaArgument {
+// a list of schema arguments. parentPath is the path of the nested block the
+// schema map belongs to, or the empty string for the root of the resource.
+func schemaArguments(schemaMap map[string]*schema.Schema, parentPath string) []schemaArgument {
 	args := []schemaArgument{}
 	for argName, argSchema := range schemaMap {
 		// skip the meta attribute
@@ -275,20 +400,212 @@ func schemaArguments(schemaMap map[string]*schema.Schema) []schemaArgument {
 		if argSchema.Computed && !argSchema.Optional {
 			continue
 		}
+		parsed, _ := parseDescription(argSchema.Description)
 		arg := schemaArgument{
 			Name:          argName,
 			Type:          schemaType(argSchema),
-			Example:       parseMetaValue(argSchema.Description, MetaExample),
-			Description:   stripMeta(argSchema.Description),
+			Example:       parsed.value(MetaExample),
+			Description:   parsed.text,
+			Custom:        customTags(parsed),
 			Optional:      argSchema.Optional,
 			ForceNew:      argSchema.ForceNew,
 			ConflictsWith: argSchema.ConflictsWith,
+			ExactlyOneOf:  argSchema.ExactlyOneOf,
+			AtLeastOneOf:  argSchema.AtLeastOneOf,
+			RequiredWith:  argSchema.RequiredWith,
+			ComputedWhen:  argSchema.ComputedWhen,
+			Sensitive:     argSchema.Sensitive,
+			Deprecated:    argSchema.Deprecated,
+			MinItems:      argSchema.MinItems,
+			MaxItems:      argSchema.MaxItems,
+			ConfigMode:    configMode(argSchema.ConfigMode),
+		}
+		if argSchema.Default != nil {
+			arg.Default = hclLiteral(argSchema.Default)
+		}
+		if isBlock(argSchema) {
+			arg.Anchor = blockAnchor(blockPath(parentPath, argName))
+			arg.Type = blockType(argSchema, arg.Anchor)
 		}
 		args = append(args, arg)
 	}
 	return args
 }
 
+// schemaBlocks scans all the schema attributes for nested blocks and parses
+// them into a tree of schema blocks. Nested blocks are walked to arbitrary
+// depth. parentPath is the path of the nested block the schema map belongs
+// to, or the empty string for the root of the resource.
+func schemaBlocks(schemaMap map[string]*schema.Schema, parentPath string) []schemaBlock {
+	blocks := []schemaBlock{}
+	for blockName, blockSchema := range schemaMap {
+		if !isBlock(blockSchema) {
+			continue
+		}
+		// skip the block if it is tagged as unexported and cannot be supplied
+		// in the config
+		computedOnly := blockSchema.Computed && !blockSchema.Optional && !blockSchema.Required
+		if computedOnly && hasMetaTag(blockSchema.Description, MetaUnexported) {
+			continue
+		}
+		elem := blockSchema.Elem.(*schema.Resource)
+		path := blockPath(parentPath, blockName)
+		block := schemaBlock{
+			Name:        blockName,
+			Path:        path,
+			Anchor:      blockAnchor(path),
+			NestingMode: "list",
+			MinItems:    blockSchema.MinItems,
+			MaxItems:    blockSchema.MaxItems,
+			Optional:    blockSchema.Optional,
+			Computed:    blockSchema.Computed,
+			Description: stripMeta(blockSchema.Description),
+			Attributes:  schemaAttributes(elem.Schema, path),
+			Arguments:   schemaArguments(elem.Schema, path),
+			Blocks:      schemaBlocks(elem.Schema, path),
+		}
+		if blockSchema.Type == schema.TypeSet {
+			block.NestingMode = "set"
+		}
+		sort.Slice(block.Arguments, func(i, j int) bool {
+			return block.Arguments[i].Name < block.Arguments[j].Name
+		})
+		sort.Slice(block.Attributes, func(i, j int) bool {
+			return block.Attributes[i].Name < block.Attributes[j].Name
+		})
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Name < blocks[j].Name
+	})
+	return blocks
+}
+
+// schemaTimeouts parses the resource's timeouts into the list of timeouts
+// that can be configured in its timeouts block, in the order create, read,
+// update, delete, and default. Only timeouts with a default are configurable.
+func schemaTimeouts(timeouts *schema.ResourceTimeout) []schemaTimeout {
+	list := []schemaTimeout{}
+	if timeouts == nil {
+		return list
+	}
+	for _, t := range []struct {
+		name        string
+		duration    *time.Duration
+		description string
+	}{
+		{schema.TimeoutCreate, timeouts.Create, "Used when creating the resource."},
+		{schema.TimeoutRead, timeouts.Read, "Used when reading the resource."},
+		{schema.TimeoutUpdate, timeouts.Update, "Used when updating the resource."},
+		{schema.TimeoutDelete, timeouts.Delete, "Used when deleting the resource."},
+		{schema.TimeoutDefault, timeouts.Default, "Used for any operation without its own timeout."},
+	} {
+		if t.duration == nil {
+			continue
+		}
+		list = append(list, schemaTimeout{
+			Name:        t.name,
+			Default:     formatDuration(*t.duration),
+			Description: t.description,
+		})
+	}
+	return list
+}
+
+// formatDuration formats a duration the way it is written in a timeouts
+// block, without trailing zero units (ie: "20m" rather than "20m0s").
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
+
+// walkSchema calls fn for every attribute of the schema map, descending into
+// nested blocks to arbitrary depth. fn receives the dot separated path of the
+// attribute from the root of the resource and its schema. Attributes are
+// visited in alphabetical order, each block before its children.
+func walkSchema(schemaMap map[string]*schema.Schema, parentPath string, fn func(path string, s *schema.Schema)) {
+	names := []string{}
+	for name := range schemaMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attrSchema := schemaMap[name]
+		path := blockPath(parentPath, name)
+		fn(path, attrSchema)
+		if isBlock(attrSchema) {
+			walkSchema(attrSchema.Elem.(*schema.Resource).Schema, path, fn)
+		}
+	}
+}
+
+// configMode returns the string representation of a schema config mode
+func configMode(mode schema.SchemaConfigMode) string {
+	switch mode {
+	case schema.SchemaConfigModeAttr:
+		return "attribute"
+	case schema.SchemaConfigModeBlock:
+		return "block"
+	default:
+		return "auto"
+	}
+}
+
+// isBlock returns whether or not the schema defines a nested block. Only
+// lists and sets of *schema.Resource are blocks; a map of *schema.Resource
+// is treated by Terraform as a map of strings.
+func isBlock(s *schema.Schema) bool {
+	if s.Type != schema.TypeList && s.Type != schema.TypeSet {
+		return false
+	}
+	_, ok := s.Elem.(*schema.Resource)
+	return ok
+}
+
+// blockPath joins the path of a parent block and the name of a child block
+// into the child's dot separated path.
+func blockPath(parentPath string, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + "." + name
+}
+
+// blockAnchor returns the anchor of the section documenting the nested block
+// at the supplied path.
+func blockAnchor(path string) string {
+	return "block-" + strings.Replace(path, ".", "-", -1)
+}
+
+// attributeAnchor returns the anchor of the list item of the attribute at the
+// dot separated path (ie: "rule.priority" => "attr-rule-priority"). Block
+// indexes in the path are ignored (ie: "rule.0.priority").
+func attributeAnchor(path string) string {
+	parts := []string{}
+	for _, part := range strings.Split(path, ".") {
+		if _, numErr := strconv.Atoi(part); numErr != nil {
+			parts = append(parts, part)
+		}
+	}
+	return "attr-" + strings.Join(parts, "-")
+}
+
+// blockType returns the markdown formatted type of a nested block, linking
+// to the section documenting the block.
+func blockType(s *schema.Schema, anchor string) string {
+	collection := "`schema.TypeList`"
+	if s.Type == schema.TypeSet {
+		collection = "`schema.TypeSet`"
+	}
+	return fmt.Sprintf("%s of [blocks](#%s)", collection, anchor)
+}
+
 // schemaType parses the schema definition for its type and returns a string
 // representation of the type with markdown formatting. If the type is simple
 // (ie: schema.TypeBool), then the output string will just be that escaped
@@ -351,23 +668,22 @@ func schemaType(s *schema.Schema) string {
 	}
 }
 
-// openFile reads the outFile of the supplied goroutineBase and attempts
-// to open it for writing. If the file does not exist, it will be created.
-// If the file already exists, it will be truncated when opened. An error is
-// returned if the file could not be opened.
-func openFile(r goroutineBase) (*os.File, error) {
+// renderTemplate executes the template of the supplied goroutineBase with
+// the supplied data and returns the rendered output for its outFile. The
+// returned error is set if the template could not be executed.
+func renderTemplate(r goroutineBase, data interface{}) renderedFile {
+	rendered := renderedFile{path: r.outFile}
+
 	// outFile should be defined
 	if r.outFile == "" {
-		return nil, fmt.Errorf(
+		rendered.err = fmt.Errorf(
 			"Cannot generate file. No outfile specified.",
 		)
+		return rendered
 	}
-	// attempt to open the output file for writing to dump our template. If the
-	// file already exists, overwrite its contents.
-	return os.OpenFile(
-		r.outFile,
-		// Write only, create file if doesn't exist, truncate file when opened
-		os.O_WRONLY|os.O_CREATE|os.O_TRUNC,
-		0775,
-	)
+
+	out := bytes.Buffer{}
+	rendered.err = r.template.ExecuteTemplate(&out, r.templateName, data)
+	rendered.content = out.Bytes()
+	return rendered
 }
@@ -0,0 +1,279 @@
+package autodoc
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Test Helpers
+// -----------------------------------------------------------------------------
+
+func resourceFoo() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the foo @EXAMPLE bar",
+			},
+			"rule": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MinItems:    1,
+				MaxItems:    3,
+				Description: "Routing rules",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"priority": &schema.Schema{
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"condition": &schema.Schema{
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"field": &schema.Schema{
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"state": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// schemaBlocks
+// -----------------------------------------------------------------------------
+
+// Ensures nested blocks are walked to arbitrary depth and carry their nesting
+// mode, item limits, and child arguments.
+func TestSchemaBlocks_Nested(t *testing.T) {
+	blocks := schemaBlocks(resourceFoo().Schema, "")
+	if len(blocks) != 2 {
+		t.Fatalf(
+			"schemaBlocks did not return the correct output. Expected [2] "+
+				"blocks, got [%d].",
+			len(blocks),
+		)
+	}
+	rule := blocks[0]
+	if rule.Path != "rule" || rule.NestingMode != "list" ||
+		rule.MinItems != 1 || rule.MaxItems != 3 {
+		t.Fatalf(
+			"schemaBlocks did not return the correct output. Unexpected "+
+				"block [%+v].",
+			rule,
+		)
+	}
+	if len(rule.Arguments) != 2 || rule.Arguments[0].Name != "condition" {
+		t.Fatalf(
+			"schemaBlocks did not return the correct output. Unexpected "+
+				"arguments [%+v].",
+			rule.Arguments,
+		)
+	}
+	if len(rule.Blocks) != 1 {
+		t.Fatalf(
+			"schemaBlocks did not return the correct output. Expected [1] "+
+				"child block, got [%d].",
+			len(rule.Blocks),
+		)
+	}
+	condition := rule.Blocks[0]
+	if condition.Path != "rule.condition" ||
+		condition.Anchor != "block-rule-condition" ||
+		condition.NestingMode != "set" {
+		t.Fatalf(
+			"schemaBlocks did not return the correct output. Unexpected "+
+				"block [%+v].",
+			condition,
+		)
+	}
+	if rule.Arguments[0].Anchor != condition.Anchor {
+		t.Fatalf(
+			"schemaBlocks did not return the correct output. Expected "+
+				"argument anchor [%s], got [%s].",
+			condition.Anchor,
+			rule.Arguments[0].Anchor,
+		)
+	}
+}
+
+// Ensures the built-in blocks partial renders a section per block
+func TestBlocksPartial(t *testing.T) {
+	templatesDir := writeTemplates(t, map[string]string{
+		"resource.md.template": `{{template "_blocks.template" .}}`,
+	})
+	defer os.RemoveAll(templatesDir)
+	templates, err := parseTemplates(Options{
+		TemplatesDir: templatesDir,
+		TemplateExt:  defaultTemplateFileExt,
+	})
+	if err != nil {
+		t.Fatalf("parseTemplates returned an error: [%s]", err)
+	}
+	data := schemaDocData{
+		Blocks: schemaBlocks(resourceFoo().Schema, ""),
+	}
+	out := bytes.Buffer{}
+	execErr := templates.ExecuteTemplate(
+		&out,
+		resourceMdTemplate+defaultTemplateFileExt,
+		data,
+	)
+	if execErr != nil {
+		t.Fatalf("blocks partial returned an error: [%s]", execErr)
+	}
+	for _, expected := range []string{
+		`<a id="block-rule"></a>`,
+		`<a id="block-rule-condition"></a>`,
+		`<a id="block-status"></a>`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf(
+				"blocks partial did not return the correct output. Expected "+
+					"[%s] in output:\n%s",
+				expected,
+				out.String(),
+			)
+		}
+	}
+}
+
+// Ensures the argument partial renders the default, item counts, and
+// constraints of an argument, and sensitive attributes are flagged
+func TestArgumentConstraints(t *testing.T) {
+	resource := resourceFoo()
+	resource.Schema["token"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Sensitive:     true,
+		Default:       "abc",
+		ConflictsWith: []string{"name"},
+		Deprecated:    "Use name instead.",
+	}
+	resource.Schema["tags"] = &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		MinItems:     1,
+		MaxItems:     3,
+		Elem:         &schema.Schema{Type: schema.TypeString},
+		ExactlyOneOf: []string{"name", "tags"},
+		RequiredWith: []string{"token"},
+		ConfigMode:   schema.SchemaConfigModeAttr,
+	}
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"] = resource
+
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/foo_bar.md")
+	for _, expected := range []string{
+		"* `token` - (Optional, Sensitive) `schema.TypeString`. Defaults " +
+			"to `\"abc\"`. Conflicts with `name`. **Deprecated:** Use name " +
+			"instead.\n",
+		"* `tags` - (Optional) `schema.TypeList` of `schema.TypeString`. " +
+			"Between 1 and 3 items. Exactly one of `name`, `tags` must be " +
+			"set. Requires `token`. Set with attribute syntax " +
+			"(`tags = [...]`) instead of blocks.\n",
+		"* <a id=\"attr-token\"></a>`token` - (Sensitive) `schema.TypeString`. **Deprecated:** Use " +
+			"name instead.\n",
+	} {
+		if !strings.Contains(string(resourceDoc), expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Expected [%s] in output:\n%s",
+				expected,
+				string(resourceDoc),
+			)
+		}
+	}
+}
+
+// Ensures the default templates render the timeouts and import sections from
+// the resource definition
+func TestDocumentWithOptions_ImportAndTimeouts(t *testing.T) {
+	create := 20 * time.Minute
+	delete := 90 * time.Minute
+	provider := providerFoo()
+	resource := provider.ResourcesMap["foo_bar"]
+	resource.Description = "A foo. @IMPORT_ID <region>/<name>"
+	resource.Importer = &schema.ResourceImporter{}
+	resource.Timeouts = &schema.ResourceTimeout{
+		Create: &create,
+		Delete: &delete,
+	}
+
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/foo_bar.md")
+	for _, expected := range []string{
+		"# foo_bar\n\nA foo.\n\n",
+		"## Timeouts\n",
+		"* `create` - (Defaults to 20m) Used when creating the resource.\n" +
+			"* `delete` - (Defaults to 1h30m) Used when deleting the resource.\n",
+		"## Import\n",
+		"terraform import foo_bar.example <region>/<name>\n",
+	} {
+		if !strings.Contains(string(resourceDoc), expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Expected [%s] in output:\n%s",
+				expected,
+				string(resourceDoc),
+			)
+		}
+	}
+
+	dataSourceDoc, _ := fs.ReadFile("/out/docs/datasources/foo_baz.md")
+	for _, unexpected := range []string{"## Timeouts", "## Import"} {
+		if strings.Contains(string(dataSourceDoc), unexpected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Unexpected [%s] in output:\n%s",
+				unexpected,
+				string(dataSourceDoc),
+			)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package autodoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the example generation, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Indentation of one level of the generated HCL example
+const exampleIndent = "  "
+
+// Prefix of an @EXAMPLE value that is used verbatim as an HCL expression (ie:
+// "@EXAMPLE =var.region")
+const exampleExprPrefix = "="
+
+// -----------------------------------------------------------------------------
+// Example Utility Functions
+// -----------------------------------------------------------------------------
+
+// exampleHCL generates a complete HCL configuration block for a provider,
+// resource, or data source. Arguments are set to their @EXAMPLE values, as
+// formatted by exampleValue. Required arguments without an example
+// are set to a placeholder value appropriate to their type. Nested blocks are
+// included when they are required or when any of their arguments has an
+// example. schemaType should be one of the typeXxx constants; for the provider
+// name is the type name of the provider in the config.
+//
+// The generated block is parsed with the HCL parser. An error is returned if
+// it is not syntactically valid (ie: an @EXAMPLE value is malformed).
+func exampleHCL(schemaType int, name string, schemaMap map[string]*schema.Schema) (string, error) {
+	var header string
+	switch schemaType {
+	case typeProvider:
+		header = "provider " + hclString(name)
+	case typeResource:
+		header = "resource " + hclString(name) + " " + hclString("example")
+	default:
+		header = "data " + hclString(name) + " " + hclString("example")
+	}
+
+	b := strings.Builder{}
+	b.WriteString(header + " {\n")
+	writeExampleBody(&b, schemaMap, exampleIndent)
+	b.WriteString("}\n")
+	example := b.String()
+
+	_, diags := hclsyntax.ParseConfig(
+		[]byte(example),
+		name+".tf",
+		hcl.Pos{Line: 1, Column: 1},
+	)
+	if diags.HasErrors() {
+		return "", fmt.Errorf(
+			"Example for [%s] is not valid HCL. Check the @EXAMPLE values of "+
+				"its arguments. Error: [%s]",
+			name,
+			diags.Error(),
+		)
+	}
+	return example, nil
+}
+
+// writeExampleBody writes the arguments and nested blocks of the schema map
+// to the example, each line prefixed with indent. Arguments are written
+// first with their equals signs aligned, followed by the nested blocks.
+func writeExampleBody(b *strings.Builder, schemaMap map[string]*schema.Schema, indent string) {
+	names := []string{}
+	for name := range schemaMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// arguments
+	argNames := []string{}
+	argValues := map[string]string{}
+	nameWidth := 0
+	for _, name := range names {
+		s := schemaMap[name]
+		if name == MetaAttribute || !isExampleArgument(s) {
+			continue
+		}
+		var value string
+		if isBlock(s) {
+			// blocks in attribute syntax are written as a list of objects
+			if s.ConfigMode != schema.SchemaConfigModeAttr || !exampleBlockNeeded(s) {
+				continue
+			}
+			value = exampleObjectList(s, indent)
+		} else {
+			value = exampleValue(s, parseMetaValue(s.Description, MetaExample))
+			if value == "" && s.Required {
+				value = examplePlaceholder(s)
+			}
+			if value == "" {
+				continue
+			}
+		}
+		argNames = append(argNames, name)
+		argValues[name] = value
+		if len(name) > nameWidth {
+			nameWidth = len(name)
+		}
+	}
+	for _, name := range argNames {
+		fmt.Fprintf(b, "%s%-*s = %s\n", indent, nameWidth, name, argValues[name])
+	}
+
+	// nested blocks
+	written := len(argNames) > 0
+	for _, name := range names {
+		s := schemaMap[name]
+		if !isBlock(s) || s.ConfigMode == schema.SchemaConfigModeAttr {
+			continue
+		}
+		if !isExampleArgument(s) || !exampleBlockNeeded(s) {
+			continue
+		}
+		for i := 0; i < exampleBlockCount(s); i++ {
+			if written {
+				b.WriteString("\n")
+			}
+			b.WriteString(indent + name + " {\n")
+			writeExampleBody(b, s.Elem.(*schema.Resource).Schema, indent+exampleIndent)
+			b.WriteString(indent + "}\n")
+			written = true
+		}
+	}
+}
+
+// exampleValue returns the HCL expression of an @EXAMPLE value. Values
+// prefixed with "=" are used verbatim, without the prefix. Other values are
+// example values: string arguments are quoted unless the value is already a
+// quoted string (ie: "foo"), and other arguments are quoted unless the value
+// is a valid HCL expression that does not reference anything (ie: 10, true,
+// or ["a", "b"]). The empty string is returned for an empty value.
+func exampleValue(s *schema.Schema, value string) string {
+	if value == "" {
+		return ""
+	}
+	if strings.HasPrefix(value, exampleExprPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(value, exampleExprPrefix))
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(value), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() || len(expr.Variables()) > 0 {
+		return hclString(value)
+	}
+	if s.Type == schema.TypeString {
+		if _, ok := expr.(*hclsyntax.TemplateExpr); !ok || !strings.HasPrefix(value, `"`) {
+			return hclString(value)
+		}
+	}
+	return value
+}
+
+// exampleObjectList returns the example value of a nested block written in
+// attribute syntax: a list of objects. indent is the indentation of the
+// argument.
+func exampleObjectList(s *schema.Schema, indent string) string {
+	b := strings.Builder{}
+	b.WriteString("[\n")
+	for i := 0; i < exampleBlockCount(s); i++ {
+		b.WriteString(indent + exampleIndent + "{\n")
+		writeExampleBody(&b, s.Elem.(*schema.Resource).Schema, indent+exampleIndent+exampleIndent)
+		b.WriteString(indent + exampleIndent + "},\n")
+	}
+	b.WriteString(indent + "]")
+	return b.String()
+}
+
+// isExampleArgument returns whether or not the attribute can be set in the
+// config. Computed only attributes cannot.
+func isExampleArgument(s *schema.Schema) bool {
+	return !s.Computed || s.Optional
+}
+
+// exampleBlockNeeded returns whether or not a nested block is included in the
+// example. Required blocks are always included; optional blocks are included
+// when any of their arguments, at any depth, has an example.
+func exampleBlockNeeded(s *schema.Schema) bool {
+	if s.Required {
+		return true
+	}
+	hasExample := false
+	walkSchema(s.Elem.(*schema.Resource).Schema, "", func(path string, attrSchema *schema.Schema) {
+		if isExampleArgument(attrSchema) && parseMetaValue(attrSchema.Description, MetaExample) != "" {
+			hasExample = true
+		}
+	})
+	return hasExample
+}
+
+// exampleBlockCount returns the number of times a nested block is repeated
+// in the example: its minimum number of items, and at least once.
+func exampleBlockCount(s *schema.Schema) int {
+	if s.MinItems > 1 {
+		return s.MinItems
+	}
+	return 1
+}
+
+// examplePlaceholder returns an HCL literal of the schema's type to use as
+// the value of a required argument without an example.
+func examplePlaceholder(s *schema.Schema) string {
+	switch s.Type {
+	case schema.TypeBool:
+		return "true"
+	case schema.TypeInt:
+		return "1"
+	case schema.TypeFloat:
+		return "1.5"
+	case schema.TypeList, schema.TypeSet:
+		if elem, ok := s.Elem.(*schema.Schema); ok {
+			return "[" + examplePlaceholder(elem) + "]"
+		}
+		return "[]"
+	case schema.TypeMap:
+		value := hclString("value")
+		if elem, ok := s.Elem.(*schema.Schema); ok {
+			value = examplePlaceholder(elem)
+		}
+		return "{ key = " + value + " }"
+	default:
+		return hclString("example")
+	}
+}
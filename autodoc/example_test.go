@@ -0,0 +1,114 @@
+package autodoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// HCL Examples
+// -----------------------------------------------------------------------------
+
+// Ensures the example includes arguments with examples, placeholders for
+// required arguments, and nested blocks that are required or have examples
+func TestExampleHCL(t *testing.T) {
+	resource := resourceFoo()
+	rule := resource.Schema["rule"].Elem.(*schema.Resource)
+	rule.Schema["priority"].Description = "Priority @EXAMPLE 10"
+	resource.Schema["labels"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Required: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	resource.Schema["enabled"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+
+	expected := `resource "foo_bar" "example" {
+  labels = { key = "example" }
+  name   = "bar"
+
+  rule {
+    priority = 10
+
+    condition {
+      field = "example"
+    }
+  }
+}
+`
+	actual, err := exampleHCL(typeResource, "foo_bar", resource.Schema)
+	if err != nil {
+		t.Fatalf("exampleHCL returned an error: [%s]", err)
+	}
+	if actual != expected {
+		t.Fatalf(
+			"exampleHCL did not return the correct output. Expected:\n%s\n"+
+				"got:\n%s",
+			expected,
+			actual,
+		)
+	}
+}
+
+// Ensures example values are quoted unless they are literals of the
+// argument's type or raw expressions
+func TestExampleValue(t *testing.T) {
+	str := &schema.Schema{Type: schema.TypeString}
+	num := &schema.Schema{Type: schema.TypeInt}
+	list := &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}}
+	for _, c := range []struct {
+		s        *schema.Schema
+		value    string
+		expected string
+	}{
+		{str, "bar", `"bar"`},
+		{str, "my-foo", `"my-foo"`},
+		{str, "my foo", `"my foo"`},
+		{str, "10", `"10"`},
+		{str, `"quoted"`, `"quoted"`},
+		{str, "=var.region", "var.region"},
+		{num, "10", "10"},
+		{num, "ten", `"ten"`},
+		{list, `["a", "b"]`, `["a", "b"]`},
+		{str, "", ""},
+	} {
+		if actual := exampleValue(c.s, c.value); actual != c.expected {
+			t.Fatalf(
+				"exampleValue did not return the correct output for [%s]. "+
+					"Expected [%s], got [%s].",
+				c.value,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
+
+// Ensures a malformed raw @EXAMPLE expression fails the generation
+func TestDocumentWithOptions_MalformedExample(t *testing.T) {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Schema["name"].Description = `@EXAMPLE ="bar`
+
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "is not valid HCL") {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Got %v.",
+			errs,
+		)
+	}
+	if len(fs.Files()) != 0 {
+		t.Fatalf(
+			"DocumentWithOptions wrote files despite errors. Got %v.",
+			fs.Files(),
+		)
+	}
+}
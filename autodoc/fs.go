@@ -0,0 +1,184 @@
+package autodoc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FileSystem is the filesystem autodoc writes its generated documentation
+// to. Implementations must be safe for concurrent use; documentation files
+// are generated from multiple goroutines.
+type FileSystem interface {
+	// ReadFile returns the contents of the named file
+	ReadFile(name string) ([]byte, error)
+	// WriteFile writes data to the named file, creating it if necessary. If
+	// the file already exists, it is truncated before writing.
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	// ReadDir returns the sorted names of the files in the named directory.
+	// Sub directories are not included.
+	ReadDir(name string) ([]string, error)
+	// MkdirAll creates the named directory and any missing parents. It does
+	// nothing if the directory already exists.
+	MkdirAll(name string, perm os.FileMode) error
+	// Rename moves the file at oldName to newName, replacing any existing
+	// file at newName
+	Rename(oldName string, newName string) error
+	// Remove removes the named file. An error satisfying os.IsNotExist is
+	// returned if the file does not exist.
+	Remove(name string) error
+}
+
+// -----------------------------------------------------------------------------
+// OS FileSystem
+// -----------------------------------------------------------------------------
+
+// OSFileSystem is a FileSystem backed by the operating system's filesystem.
+// This is the default output filesystem.
+type OSFileSystem struct{}
+
+// ReadFile returns the contents of the named file
+func (OSFileSystem) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(name)
+}
+
+// WriteFile writes data to the named file, creating it if necessary
+func (OSFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return ioutil.WriteFile(name, data, perm)
+}
+
+// ReadDir returns the sorted names of the files in the named directory
+func (OSFileSystem) ReadDir(name string) ([]string, error) {
+	infos, err := ioutil.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+// MkdirAll creates the named directory and any missing parents
+func (OSFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	return os.MkdirAll(name, perm)
+}
+
+// Rename moves the file at oldName to newName
+func (OSFileSystem) Rename(oldName string, newName string) error {
+	return os.Rename(oldName, newName)
+}
+
+// Remove removes the named file
+func (OSFileSystem) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// -----------------------------------------------------------------------------
+// Memory FileSystem
+// -----------------------------------------------------------------------------
+
+// MemoryFileSystem is a FileSystem that keeps all files in memory. It is
+// intended for generating documentation in-process, ie: from unit tests.
+type MemoryFileSystem struct {
+	// guards files
+	mu sync.Mutex
+	// file contents keyed by cleaned file path
+	files map[string][]byte
+}
+
+// NewMemoryFileSystem returns an empty in-memory filesystem
+func NewMemoryFileSystem() *MemoryFileSystem {
+	return &MemoryFileSystem{
+		files: map[string][]byte{},
+	}
+}
+
+// ReadFile returns the contents of the named file. An error satisfying
+// os.IsNotExist is returned if the file does not exist.
+func (m *MemoryFileSystem) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return append([]byte{}, data...), nil
+}
+
+// WriteFile stores a copy of data as the contents of the named file
+func (m *MemoryFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[filepath.Clean(name)] = append([]byte{}, data...)
+	return nil
+}
+
+// ReadDir returns the sorted names of the files in the named directory. An
+// error satisfying os.IsNotExist is returned if the directory contains no
+// files.
+func (m *MemoryFileSystem) ReadDir(name string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dir := filepath.Clean(name)
+	names := []string{}
+	for path := range m.files {
+		if filepath.Dir(path) == dir {
+			names = append(names, filepath.Base(path))
+		}
+	}
+	if len(names) == 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// MkdirAll does nothing. Directories exist implicitly in the memory
+// filesystem as long as they contain files.
+func (m *MemoryFileSystem) MkdirAll(name string, perm os.FileMode) error {
+	return nil
+}
+
+// Rename moves the file at oldName to newName. An error satisfying
+// os.IsNotExist is returned if the file at oldName does not exist.
+func (m *MemoryFileSystem) Rename(oldName string, newName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[filepath.Clean(oldName)]
+	if !ok {
+		return &os.LinkError{Op: "rename", Old: oldName, New: newName, Err: os.ErrNotExist}
+	}
+	delete(m.files, filepath.Clean(oldName))
+	m.files[filepath.Clean(newName)] = data
+	return nil
+}
+
+// Remove removes the named file. An error satisfying os.IsNotExist is
+// returned if the file does not exist.
+func (m *MemoryFileSystem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.files[filepath.Clean(name)]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(m.files, filepath.Clean(name))
+	return nil
+}
+
+// Files returns the sorted list of paths of all files in the filesystem
+func (m *MemoryFileSystem) Files() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := []string{}
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
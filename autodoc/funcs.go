@@ -0,0 +1,241 @@
+package autodoc
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// NOTE(ALL): If you make modifications to the template functions, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Characters that are escaped by the escapeMarkdown template function
+const markdownSpecialChars = "\\`*_[]<>|#"
+
+// -----------------------------------------------------------------------------
+// Template Function Utility Functions
+// -----------------------------------------------------------------------------
+
+// templateFuncs returns the functions available to all templates: the
+// built-in functions, over-ridden and extended by the functions in the
+// options
+func templateFuncs(opts Options) template.FuncMap {
+	// the page links depend on the layout of the site generator. An unknown
+	// profile is reported before any page is rendered; other output (ie: the
+	// changelog) links to the mkdocs layout.
+	site, siteErr := lookupSiteGenerator(opts.Profile)
+	if siteErr != nil {
+		site = mkdocsSite{}
+	}
+
+	funcs := template.FuncMap{
+		"escapeMarkdown": escapeMarkdown,
+		"escapeHTML":     html.EscapeString,
+		"tableCell":      tableCell,
+		"slug":           slug,
+		"blockAnchor":    blockAnchor,
+		"code":           inlineCode,
+		"codeFence":      codeFence,
+		"indent":         indent,
+		"wrap":           wrap,
+		"plural":         plural,
+		"join":           join,
+		"sort":           sortStrings,
+		"hcl":            hclLiteral,
+		"providerTitle":  providerTitle,
+		"admonition":     site.admonition,
+		"resourcePath": func(from interface{}, name string) string {
+			return pageLinkPath(site, from, typeResource, name)
+		},
+		"dataSourcePath": func(from interface{}, name string) string {
+			return pageLinkPath(site, from, typeDataSource, name)
+		},
+		"resourceLink": func(from interface{}, name string) string {
+			return markdownLink(inlineCode(name), pageLinkPath(site, from, typeResource, name))
+		},
+		"dataSourceLink": func(from interface{}, name string) string {
+			return markdownLink(inlineCode(name), pageLinkPath(site, from, typeDataSource, name))
+		},
+	}
+	for name, fn := range opts.Funcs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// escapeMarkdown escapes the characters that have a special meaning in
+// Markdown with a backslash, so the text is rendered literally
+func escapeMarkdown(s string) string {
+	b := strings.Builder{}
+	for _, r := range s {
+		if strings.ContainsRune(markdownSpecialChars, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// tableCell formats text for a Markdown table cell: pipes are escaped and
+// line breaks are replaced with <br> so the cell stays on one line
+func tableCell(s string) string {
+	s = strings.TrimSpace(strings.Replace(s, "\r\n", "\n", -1))
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
+
+// slug returns the anchor mkdocs generates for a heading with the text (ie:
+// "Argument Reference" => "argument-reference"): lower case, with characters
+// other than letters, digits, underscores, and hyphens removed, and runs of
+// whitespace and hyphens replaced by a single hyphen
+func slug(s string) string {
+	b := strings.Builder{}
+	separator := false
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		switch {
+		case r == '-' || r == ' ' || r == '\t' || r == '\n':
+			separator = true
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			if separator && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			separator = false
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// inlineCode formats text as a Markdown code span. Text containing
+// backticks is delimited by a longer run of backticks.
+func inlineCode(s string) string {
+	fence := strings.Repeat("`", longestBacktickRun(s)+1)
+	if strings.Contains(s, "`") {
+		return fence + " " + s + " " + fence
+	}
+	return fence + s + fence
+}
+
+// codeFence formats text as a fenced Markdown code block of the language.
+// The fence is longer than any run of backticks in the text.
+func codeFence(lang string, s string) string {
+	length := longestBacktickRun(s) + 1
+	if length < 3 {
+		length = 3
+	}
+	fence := strings.Repeat("`", length)
+	return fence + lang + "\n" + strings.TrimRight(s, "\n") + "\n" + fence
+}
+
+// longestBacktickRun returns the length of the longest run of backticks in
+// the text
+func longestBacktickRun(s string) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r != '`' {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+// indent prefixes every non-empty line of the text with the number of spaces
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines {
+		if line != "" {
+			lines[idx] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap word-wraps each line of the text to the width. Existing line breaks
+// are kept, and words longer than the width are not broken.
+func wrap(width int, s string) string {
+	if width <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for idx, line := range lines {
+		b := strings.Builder{}
+		lineLen := 0
+		for _, word := range strings.Fields(line) {
+			switch {
+			case lineLen == 0:
+			case lineLen+1+len(word) > width:
+				b.WriteByte('\n')
+				lineLen = 0
+			default:
+				b.WriteByte(' ')
+				lineLen++
+			}
+			b.WriteString(word)
+			lineLen += len(word)
+		}
+		lines[idx] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// plural returns the singular form if the count is 1, and the plural form
+// otherwise (ie: {{len .Arguments}} {{plural (len .Arguments) "argument"
+// "arguments"}})
+func plural(count int, singular string, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// join joins the list with the separator. The list is the last argument, so
+// it can be piped in (ie: {{.ConflictsWith | join ", "}}).
+func join(separator string, list []string) string {
+	return strings.Join(list, separator)
+}
+
+// sortStrings returns a sorted copy of the list
+func sortStrings(list []string) []string {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// markdownLink formats a Markdown link
+func markdownLink(text string, url string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+// pageLinkPath returns the path of the page documenting a resource or data
+// source, relative to the page being rendered. from is the template data of
+// the page being rendered ($ in a template), including guides; links from
+// any other page (ie: the changelog) are relative to the docs directory.
+// schemaType should be typeResource or typeDataSource.
+func pageLinkPath(site siteGenerator, from interface{}, schemaType int, name string) string {
+	fromDir := "."
+	switch data := from.(type) {
+	case schemaDocData:
+		fromDir = path.Dir(site.pagePath(data.SchemaType, data.Name))
+	case guideDocData:
+		fromDir = path.Dir(data.Path)
+	}
+	to := site.pagePath(schemaType, name)
+	rel, relErr := filepath.Rel(filepath.FromSlash(fromDir), filepath.FromSlash(to))
+	if relErr != nil {
+		return to
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,101 @@
+package autodoc
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// -----------------------------------------------------------------------------
+// Template Functions
+// -----------------------------------------------------------------------------
+
+// Ensures each built-in template function returns the correct output
+func TestTemplateFuncs(t *testing.T) {
+	opts, _ := Options{RootDir: "/out"}.withDefaults()
+	cases := []struct {
+		template string
+		data     interface{}
+		expected string
+	}{
+		{`{{escapeMarkdown "a_b *c* <d> | #e"}}`, nil, `a\_b \*c\* \<d\> \| \#e`},
+		{`{{escapeHTML "<a & b>"}}`, nil, "&lt;a &amp; b&gt;"},
+		{`{{tableCell "a | b\nc\r\nd "}}`, nil, `a \| b<br>c<br>d`},
+		{`{{slug "Argument Reference: foo_bar (v1.2)"}}`, nil, "argument-reference-foo_bar-v12"},
+		{`{{blockAnchor "rule.filter"}}`, nil, "block-rule-filter"},
+		{"{{code \"foo\"}} {{code \"a`b\"}}", nil, "`foo` `` a`b ``"},
+		{"{{codeFence \"hcl\" \"a = 1\\n\"}}", nil, "```hcl\na = 1\n```"},
+		{"{{codeFence \"md\" \"```x```\"}}", nil, "````md\n```x```\n````"},
+		{`{{indent 2 "a\n\nb"}}`, nil, "  a\n\n  b"},
+		{`{{wrap 10 "the quick brown fox jumps\nover"}}`, nil, "the quick\nbrown fox\njumps\nover"},
+		{`{{plural 1 "item" "items"}} {{plural 2 "item" "items"}}`, nil, "item items"},
+		{`{{. | sort | join ", "}}`, []string{"b", "c", "a"}, "a, b, c"},
+		{`{{hcl .}}`, map[string]interface{}{"a": []int{1, 2}}, `{ "a" = [1, 2] }`},
+		{
+			`{{resourceLink . "foo_qux"}} {{dataSourcePath . "foo_baz"}}`,
+			schemaDocData{SchemaType: typeResource, Name: "foo_bar"},
+			"[`foo_qux`](foo_qux.md) ../datasources/foo_baz.md",
+		},
+		{
+			`{{resourcePath . "foo_qux"}} {{dataSourceLink . "foo_baz"}}`,
+			schemaDocData{SchemaType: typeProvider, Name: "foo"},
+			"resources/foo_qux.md [`foo_baz`](datasources/foo_baz.md)",
+		},
+	}
+	for _, c := range cases {
+		tmpl, parseErr := template.New("").Funcs(templateFuncs(opts)).Parse(c.template)
+		if parseErr != nil {
+			t.Fatalf("Template [%s] did not parse: [%s]", c.template, parseErr)
+		}
+		out := bytes.Buffer{}
+		if execErr := tmpl.Execute(&out, c.data); execErr != nil {
+			t.Fatalf("Template [%s] did not execute: [%s]", c.template, execErr)
+		}
+		if out.String() != c.expected {
+			t.Fatalf(
+				"Template [%s] did not return the correct output. Expected "+
+					"[%s], got [%s].",
+				c.template,
+				c.expected,
+				out.String(),
+			)
+		}
+	}
+}
+
+// Ensures the functions from the options are available to templates and
+// over-ride the built-in functions
+func TestDocumentWithOptions_Funcs(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	templatesDir := writeTemplates(t, map[string]string{
+		"registry-schema.md.template": "{{shout .Name}} {{code .Name}} " +
+			"{{resourceLink $ \"foo_bar\"}}\n",
+	})
+	defer os.RemoveAll(templatesDir)
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:      "/out",
+		Profile:      ProfileRegistry,
+		TemplatesDir: templatesDir,
+		Funcs: template.FuncMap{
+			"shout": strings.ToUpper,
+			"code":  func(s string) string { return "<code>" + s + "</code>" },
+		},
+		FileSystem: fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	expected := "FOO_BAZ <code>foo_baz</code> [`foo_bar`](../resources/bar.md)\n"
+	actual, _ := fs.ReadFile("/out/docs/data-sources/baz.md")
+	if string(actual) != expected {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"[%s], got [%s].",
+			expected,
+			string(actual),
+		)
+	}
+}
@@ -0,0 +1,423 @@
+package autodoc
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NOTE(ALL): If you make modifications to the Go API reference, be sure to
+//   update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Go package patterns
+const (
+	// Suffix of a package directory that also selects every package under it
+	// (ie: "./..." or "helper/...")
+	goPackageWildcard = "/..."
+	// Directory of the Go API reference pages, relative to the docs directory
+	goPackagesDir = "godoc"
+)
+
+// -----------------------------------------------------------------------------
+// Go API Reference Data Structs
+// -----------------------------------------------------------------------------
+
+// A Go package of the API reference, parsed from the source files in its
+// directory
+type goPackage struct {
+	// Import path of the package (ie: "github.com/foo/bar/baz"). It is the
+	// module path followed by the directory of the package in the module, or
+	// the directory itself if it is not in a module.
+	importPath string
+	// The file set the source files were parsed with, to print declarations
+	fset *token.FileSet
+	// Documentation of the exported identifiers of the package
+	doc *doc.Package
+}
+
+// -----------------------------------------------------------------------------
+// Go API Reference Utility Functions
+// -----------------------------------------------------------------------------
+
+// loadGoPackages parses the Go packages of the options' package directories,
+// sorted by import path. A directory ending in "/..." selects every package
+// under it, skipping vendor and testdata directories and directories whose
+// names start with "." or "_".
+func loadGoPackages(opts Options) ([]goPackage, error) {
+	dirs, dirsErr := goPackageDirs(opts.GoPackages)
+	if dirsErr != nil {
+		return nil, dirsErr
+	}
+	packages := []goPackage{}
+	for _, dir := range dirs {
+		pkg, pkgErr := loadGoPackage(dir)
+		if pkgErr != nil {
+			return nil, fmt.Errorf(
+				"Cannot load Go package [%s]. Error: [%s]",
+				dir,
+				pkgErr.Error(),
+			)
+		}
+		packages = append(packages, pkg)
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].importPath < packages[j].importPath
+	})
+	return packages, nil
+}
+
+// goPackageDirs expands the package patterns into the sorted, unique list of
+// package directories. A directory without a "/..." suffix is returned as it
+// is; a directory with the suffix is walked for the directories with Go
+// source files.
+func goPackageDirs(patterns []string) ([]string, error) {
+	found := map[string]bool{}
+	for _, pattern := range patterns {
+		if !strings.HasSuffix(pattern, goPackageWildcard) && pattern != "..." {
+			found[filepath.Clean(pattern)] = true
+			continue
+		}
+		root := filepath.Clean(strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/"))
+		walkErr := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			// directories without Go source files are not packages
+			if _, importErr := build.ImportDir(path, 0); importErr != nil {
+				if _, noGo := importErr.(*build.NoGoError); noGo {
+					return nil
+				}
+			}
+			found[path] = true
+			return nil
+		})
+		if walkErr != nil {
+			return nil, walkErr
+		}
+	}
+	dirs := []string{}
+	for dir := range found {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// loadGoPackage parses the non-test Go source files of the package in the
+// directory that match the build constraints, and reads the documentation of
+// its exported identifiers
+func loadGoPackage(dir string) (goPackage, error) {
+	pkg := goPackage{fset: token.NewFileSet()}
+	buildPkg, importErr := build.ImportDir(dir, 0)
+	if importErr != nil {
+		return pkg, importErr
+	}
+	files := map[string]*ast.File{}
+	for _, name := range buildPkg.GoFiles {
+		filename := filepath.Join(dir, name)
+		file, parseErr := parser.ParseFile(pkg.fset, filename, nil, parser.ParseComments)
+		if parseErr != nil {
+			return pkg, parseErr
+		}
+		files[filename] = file
+	}
+	importPath, importPathErr := goImportPath(dir)
+	if importPathErr != nil {
+		return pkg, importPathErr
+	}
+	pkg.importPath = importPath
+	pkg.doc = doc.New(&ast.Package{Name: buildPkg.Name, Files: files}, importPath, 0)
+	return pkg, nil
+}
+
+// goImportPath returns the import path of the package in the directory: the
+// path of the module containing it, from the nearest go.mod file, followed by
+// the directory relative to the module. The slash separated directory is
+// returned if it is not in a module.
+func goImportPath(dir string) (string, error) {
+	absDir, absErr := filepath.Abs(dir)
+	if absErr != nil {
+		return "", absErr
+	}
+	for modDir := absDir; ; modDir = filepath.Dir(modDir) {
+		modulePath, moduleErr := goModulePath(filepath.Join(modDir, "go.mod"))
+		if moduleErr != nil {
+			return "", moduleErr
+		}
+		if modulePath != "" {
+			rel, _ := filepath.Rel(modDir, absDir)
+			return path.Join(modulePath, filepath.ToSlash(rel)), nil
+		}
+		if filepath.Dir(modDir) == modDir {
+			return filepath.ToSlash(filepath.Clean(dir)), nil
+		}
+	}
+}
+
+// goModulePath returns the module path declared in the go.mod file, or the
+// empty string if the file does not exist
+func goModulePath(goModPath string) (string, error) {
+	file, openErr := os.Open(goModPath)
+	if os.IsNotExist(openErr) {
+		return "", nil
+	}
+	if openErr != nil {
+		return "", openErr
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return "", scanErr
+	}
+	return "", fmt.Errorf("[%s] does not declare a module path.", goModPath)
+}
+
+// goPackagePagePath returns the path of the API reference page of a Go
+// package, which is the same for every site generator
+func goPackagePagePath(importPath string) string {
+	return goPackagesDir + "/" + importPath + ".md"
+}
+
+// newGoPackagePages returns the pages of the Go packages, in the order of the
+// packages
+func newGoPackagePages(site siteGenerator, packages []goPackage) []sitePage {
+	pages := []sitePage{}
+	for _, pkg := range packages {
+		pagePath := site.pagePath(typeGoPackage, pkg.importPath)
+		pages = append(pages, sitePage{
+			Name:    pkg.importPath,
+			Path:    pagePath,
+			ID:      strings.TrimSuffix(pagePath, ".md"),
+			Summary: doc.Synopsis(pkg.doc.Doc),
+		})
+	}
+	return pages
+}
+
+// newGoPackageData returns the template data of the API reference page of a
+// Go package
+func newGoPackageData(opts Options, site siteGenerator, data siteData, pkg goPackage) goPackageDocData {
+	pkgData := goPackageDocData{
+		Name:         pkg.doc.Name,
+		ImportPath:   pkg.importPath,
+		Synopsis:     doc.Synopsis(pkg.doc.Doc),
+		Doc:          goDocMarkdown(pkg.doc.Doc),
+		Path:         site.pagePath(typeGoPackage, pkg.importPath),
+		ProviderName: opts.ProviderName,
+		Constants:    goValueDocs(pkg, pkg.doc.Consts),
+		Variables:    goValueDocs(pkg, pkg.doc.Vars),
+		Functions:    goFuncDocs(pkg, pkg.doc.Funcs),
+		Types:        []goTypeDoc{},
+		GoPackages:   data.GoPackages,
+	}
+	for _, t := range pkg.doc.Types {
+		pkgData.Types = append(pkgData.Types, goTypeDoc{
+			Name:      t.Name,
+			Anchor:    t.Name,
+			Decl:      goDecl(pkg, t.Decl),
+			Doc:       goDocMarkdown(t.Doc),
+			Constants: goValueDocs(pkg, t.Consts),
+			Variables: goValueDocs(pkg, t.Vars),
+			Functions: goFuncDocs(pkg, t.Funcs),
+			Methods:   goFuncDocs(pkg, t.Methods),
+		})
+	}
+	pkgData.FrontMatter = site.frontMatter(schemaDocData{
+		SchemaType:   typeGoPackage,
+		Name:         pkg.importPath,
+		ProviderName: opts.ProviderName,
+		Meta:         meta{Summary: pkgData.Synopsis},
+	})
+	return pkgData
+}
+
+// goValueDocs returns the documentation of the constant or variable
+// declarations, named after the identifiers they declare
+func goValueDocs(pkg goPackage, values []*doc.Value) []goDeclDoc {
+	docs := []goDeclDoc{}
+	for _, value := range values {
+		docs = append(docs, goDeclDoc{
+			Name:   strings.Join(value.Names, ", "),
+			Anchor: value.Names[0],
+			Decl:   goDecl(pkg, value.Decl),
+			Doc:    goDocMarkdown(value.Doc),
+		})
+	}
+	return docs
+}
+
+// goFuncDocs returns the documentation of the functions or methods. Methods
+// are anchored by their receiver type and name (ie: "Options.Validate").
+func goFuncDocs(pkg goPackage, funcs []*doc.Func) []goDeclDoc {
+	docs := []goDeclDoc{}
+	for _, fn := range funcs {
+		anchor := fn.Name
+		if fn.Recv != "" {
+			anchor = strings.TrimPrefix(fn.Recv, "*") + "." + fn.Name
+		}
+		docs = append(docs, goDeclDoc{
+			Name:   fn.Name,
+			Recv:   fn.Recv,
+			Anchor: anchor,
+			Decl:   goDecl(pkg, fn.Decl),
+			Doc:    goDocMarkdown(fn.Doc),
+		})
+	}
+	return docs
+}
+
+// goDecl returns the Go source of the declaration, without its doc comment,
+// function body, or unexported struct fields and interface methods
+func goDecl(pkg goPackage, decl ast.Decl) string {
+	b := bytes.Buffer{}
+	config := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if printErr := config.Fprint(&b, pkg.fset, decl); printErr != nil {
+		return ""
+	}
+	return b.String()
+}
+
+// goDocMarkdown converts a Go doc comment to Markdown. Indented blocks are
+// preformatted text and become fenced code blocks, and headings (a single
+// capitalized line without punctuation between two paragraphs) become level
+// three headings. Other paragraphs are kept as they are.
+func goDocMarkdown(text string) string {
+	paragraphs := [][]string{}
+	current := []string{}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, current)
+				current = []string{}
+			}
+			continue
+		}
+		// an indented block ends the paragraph before it, and the other way
+		// around
+		if len(current) > 0 && isGoDocIndented(line) != isGoDocIndented(current[0]) {
+			paragraphs = append(paragraphs, current)
+			current = []string{}
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, current)
+	}
+	paragraphs = mergeGoDocBlocks(paragraphs)
+
+	blocks := []string{}
+	for idx, lines := range paragraphs {
+		switch {
+		case isGoDocIndented(lines[0]):
+			blocks = append(blocks, "```\n"+strings.Join(unindentLines(lines), "\n")+"\n```")
+		case isGoDocHeading(paragraphs, idx):
+			blocks = append(blocks, "### "+lines[0])
+		default:
+			blocks = append(blocks, strings.Join(lines, "\n"))
+		}
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// mergeGoDocBlocks merges the preformatted blocks that are only separated by
+// blank lines, keeping a blank line between them
+func mergeGoDocBlocks(paragraphs [][]string) [][]string {
+	merged := [][]string{}
+	for _, lines := range paragraphs {
+		last := len(merged) - 1
+		if last >= 0 && isGoDocIndented(lines[0]) && isGoDocIndented(merged[last][0]) {
+			merged[last] = append(append(merged[last], ""), lines...)
+			continue
+		}
+		merged = append(merged, lines)
+	}
+	return merged
+}
+
+// isGoDocIndented returns whether or not the line of a doc comment is part
+// of a preformatted block
+func isGoDocIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// isGoDocHeading returns whether or not the paragraph at the index is a
+// heading, following the go/doc rules: a single line that starts with an
+// uppercase letter, ends with a letter or digit, has no punctuation other
+// than parentheses, commas, and apostrophes, is not the first paragraph, and
+// is followed by a regular paragraph
+func isGoDocHeading(paragraphs [][]string, idx int) bool {
+	if idx == 0 || idx == len(paragraphs)-1 || len(paragraphs[idx]) != 1 {
+		return false
+	}
+	if isGoDocIndented(paragraphs[idx+1][0]) {
+		return false
+	}
+	line := paragraphs[idx][0]
+	runes := []rune(line)
+	if !unicode.IsUpper(runes[0]) {
+		return false
+	}
+	last := runes[len(runes)-1]
+	if !unicode.IsLetter(last) && !unicode.IsDigit(last) {
+		return false
+	}
+	for _, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) {
+			continue
+		}
+		if !strings.ContainsRune("(),'", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// unindentLines removes the longest common whitespace prefix from the
+// non-blank lines
+func unindentLines(lines []string) []string {
+	prefix := lines[0][:len(lines[0])-len(strings.TrimLeft(lines[0], " \t"))]
+	for _, line := range lines[1:] {
+		for line != "" && !strings.HasPrefix(line, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	unindented := []string{}
+	for _, line := range lines {
+		unindented = append(unindented, strings.TrimPrefix(line, prefix))
+	}
+	return unindented
+}
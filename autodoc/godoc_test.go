@@ -0,0 +1,138 @@
+package autodoc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// Go API Reference
+// -----------------------------------------------------------------------------
+
+// goModule returns the files of a Go module with two packages, a test file,
+// and a testdata package that is skipped
+func goModule() map[string]string {
+	return map[string]string{
+		"go.mod": "module example.com/foo\n\ngo 1.12\n",
+		"foo.go": "// Package foo configures the\n// foo API.\n" +
+			"//\n// Usage\n//\n// Create a client:\n//   c := foo.NewClient()\n" +
+			"package foo\n\n" +
+			"// Default API endpoint\nconst Endpoint = \"https://foo\"\n\n" +
+			"// Client calls the foo API\ntype Client struct {\n\tName string\n\ttoken string\n}\n\n" +
+			"// NewClient returns a client\nfunc NewClient() *Client { return &Client{} }\n\n" +
+			"// Call calls the API\nfunc (c *Client) Call(path string) error { return nil }\n\n" +
+			"// Version returns the version\nfunc Version() string { return \"1\" }\n\n" +
+			"func unexported() {}\n",
+		"foo_test.go":         "package foo\n\n// Tested is not documented\nfunc Tested() {}\n",
+		"bar/bar.go":          "// Package bar is a helper\npackage bar\n",
+		"testdata/baz/baz.go": "package baz\n",
+	}
+}
+
+// Ensures doc comments are converted to Markdown paragraphs, code blocks, and
+// headings
+func TestGoDocMarkdown(t *testing.T) {
+	text := "Package foo does things.\n\nExample\n\nRun it:\n  foo -x\n\n  foo -y\nDone.\n"
+	expected := "Package foo does things.\n\n### Example\n\nRun it:\n\n" +
+		"```\nfoo -x\n\nfoo -y\n```\n\nDone.\n"
+	if actual := goDocMarkdown(text); actual != expected {
+		t.Fatalf(
+			"goDocMarkdown did not return the correct output. Expected:\n%s\ngot:\n%s",
+			expected,
+			actual,
+		)
+	}
+}
+
+// Ensures an API reference page is generated for each Go package and linked
+// from the navigation and the godoc.md index
+func TestDocumentWithOptions_GoPackages(t *testing.T) {
+	moduleDir := writeTemplates(t, goModule())
+	defer os.RemoveAll(moduleDir)
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(providerFoo(), Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+		GoPackages:   []string{filepath.Join(moduleDir, "...")},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	for path, snippets := range map[string][]string{
+		"/out/mkdocs.yml": {
+			"  - Go API:\n    - Overview: 'godoc.md'\n" +
+				"    - \"example.com/foo\": 'godoc/example.com/foo.md'\n" +
+				"    - \"example.com/foo/bar\": 'godoc/example.com/foo/bar.md'\n",
+		},
+		"/out/docs/godoc.md": {
+			"* [`example.com/foo`](godoc/example.com/foo.md) - Package foo configures the foo API.\n" +
+				"* [`example.com/foo/bar`](godoc/example.com/foo/bar.md) - Package bar is a helper\n",
+		},
+		"/out/docs/godoc/example.com/foo.md": {
+			"# package foo\n\n```go\nimport \"example.com/foo\"\n```\n\n" +
+				"Package foo configures the\nfoo API.\n\n### Usage\n\n" +
+				"Create a client:\n\n```\nc := foo.NewClient()\n```\n",
+			"## Constants\n\n```go\nconst Endpoint = \"https://foo\"\n```\n\nDefault API endpoint\n",
+			"## Functions\n\n<a id=\"Version\"></a>\n### func Version\n\n" +
+				"```go\nfunc Version() string\n```\n\nVersion returns the version\n",
+			"<a id=\"Client\"></a>\n### type Client\n\n```go\ntype Client struct {\n" +
+				"\tName string\n\t// contains filtered or unexported fields\n}\n```\n",
+			"<a id=\"NewClient\"></a>\n#### func NewClient\n\n",
+			"<a id=\"Client.Call\"></a>\n#### func (*Client) Call\n\n" +
+				"```go\nfunc (c *Client) Call(path string) error\n```\n\nCall calls the API\n",
+		},
+	} {
+		content, _ := fs.ReadFile(path)
+		for _, snippet := range snippets {
+			if !strings.Contains(string(content), snippet) {
+				t.Fatalf(
+					"DocumentWithOptions did not return the correct output. "+
+						"Expected [%s] in [%s]:\n%s",
+					snippet,
+					path,
+					string(content),
+				)
+			}
+		}
+	}
+
+	content, _ := fs.ReadFile("/out/docs/godoc/example.com/foo.md")
+	for _, name := range []string{"Tested", "func unexported", "token string"} {
+		if strings.Contains(string(content), name) {
+			t.Fatalf("DocumentWithOptions documented [%s]:\n%s", name, string(content))
+		}
+	}
+	if _, readErr := fs.ReadFile("/out/docs/godoc/example.com/foo/testdata/baz.md"); readErr == nil {
+		t.Fatalf("DocumentWithOptions documented the testdata package.")
+	}
+}
+
+// Ensures a package directory that cannot be parsed fails the generation
+func TestDocumentWithOptions_InvalidGoPackage(t *testing.T) {
+	moduleDir := writeTemplates(t, map[string]string{
+		"go.mod":    "module example.com/foo\n",
+		"broken.go": "package foo\n\nfunc {\n",
+	})
+	defer os.RemoveAll(moduleDir)
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:      "/out",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   NewMemoryFileSystem(),
+		GoPackages:   []string{moduleDir},
+	})
+	expected := "Cannot load Go package [" + moduleDir + "]."
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Expected "+
+				"[%s], got %v.",
+			expected,
+			errs,
+		)
+	}
+}
@@ -0,0 +1,277 @@
+package autodoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the guides, be sure to update the
+//   documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Front matter of guide templates
+const (
+	// Delimits the front matter at the start of a guide template
+	guideFrontMatterDelim = "---"
+	// Title of the guide in the navigation and its page front matter
+	guideTitleKey = "title"
+	// Description of the guide in its page front matter
+	guideDescriptionKey = "description"
+	// Position of the guide in the navigation. Guides are ordered by weight,
+	// and then by title.
+	guideWeightKey = "weight"
+)
+
+// -----------------------------------------------------------------------------
+// Guide Data Structs
+// -----------------------------------------------------------------------------
+
+// A hand-written guide, loaded from a template in the guides templates
+// directory
+type guide struct {
+	// Name of the guide, its template file name without the extensions
+	name string
+	// Name of the guide template in the template tree (ie:
+	// "guides/authentication.md.template")
+	template string
+	// Title from the front matter. Defaults to the name.
+	title string
+	// Description from the front matter
+	description string
+	// Weight from the front matter
+	weight int
+	// Body of the template, without its front matter
+	body string
+}
+
+// -----------------------------------------------------------------------------
+// Guide Utility Functions
+// -----------------------------------------------------------------------------
+
+// loadGuides reads the guide templates (*.md with the template extension) in
+// the guides templates directory, sorted by weight and then by title. A
+// missing directory is not an error; there are no guides.
+func loadGuides(opts Options) ([]guide, error) {
+	guides := []guide{}
+	dir := filepath.Join(opts.TemplatesDir, guidesTemplatesDir)
+	if _, statErr := os.Stat(dir); os.IsNotExist(statErr) {
+		return guides, nil
+	}
+	matches, globErr := filepath.Glob(filepath.Join(dir, "*.md"+opts.TemplateExt))
+	if globErr != nil {
+		return nil, globErr
+	}
+	for _, match := range matches {
+		g, guideErr := readGuide(opts, match)
+		if guideErr != nil {
+			return nil, guideErr
+		}
+		guides = append(guides, g)
+	}
+	sort.SliceStable(guides, func(i, j int) bool {
+		if guides[i].weight != guides[j].weight {
+			return guides[i].weight < guides[j].weight
+		}
+		return guides[i].title < guides[j].title
+	})
+	return guides, nil
+}
+
+// readGuide reads the guide template at the path and parses its front matter
+func readGuide(opts Options, path string) (guide, error) {
+	base := filepath.Base(path)
+	g := guide{
+		name:     strings.TrimSuffix(base, ".md"+opts.TemplateExt),
+		template: guidesTemplatesDir + "/" + base,
+	}
+	content, readErr := ioutil.ReadFile(path)
+	if readErr != nil {
+		return g, readErr
+	}
+	if parseErr := parseGuideFrontMatter(&g, string(content)); parseErr != nil {
+		return g, fmt.Errorf(
+			"Cannot load guide [%s]. Error: [%s]",
+			path,
+			parseErr.Error(),
+		)
+	}
+	if g.title == "" {
+		g.title = g.name
+	}
+	return g, nil
+}
+
+// parseGuideFrontMatter sets the title, description, and weight of the guide
+// from the YAML front matter at the start of the template content, and its
+// body to the rest of the content. Content without front matter is the body.
+// Only single line "key: value" pairs are supported; values may be quoted.
+func parseGuideFrontMatter(g *guide, content string) error {
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	if !strings.HasPrefix(content, guideFrontMatterDelim+"\n") {
+		g.body = content
+		return nil
+	}
+	rest := content[len(guideFrontMatterDelim)+1:]
+	end := strings.Index("\n"+rest, "\n"+guideFrontMatterDelim+"\n")
+	if end < 0 {
+		return fmt.Errorf("Front matter is not terminated.")
+	}
+	g.body = rest[end+len(guideFrontMatterDelim)+1:]
+
+	for _, line := range strings.Split(strings.TrimSuffix(rest[:end], "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		idx := strings.Index(line, ":")
+		if idx < 0 {
+			return fmt.Errorf("Front matter line [%s] is not a key: value pair.", line)
+		}
+		key := strings.TrimSpace(line[:idx])
+		value := unquoteYAML(strings.TrimSpace(line[idx+1:]))
+		switch key {
+		case guideTitleKey:
+			g.title = value
+		case guideDescriptionKey:
+			g.description = value
+		case guideWeightKey:
+			weight, atoiErr := strconv.Atoi(value)
+			if atoiErr != nil {
+				return fmt.Errorf("Weight [%s] is not an integer.", value)
+			}
+			g.weight = weight
+		default:
+			return fmt.Errorf(
+				"Unknown front matter key [%s]. Expected one of [%s, %s, %s].",
+				key,
+				guideDescriptionKey,
+				guideTitleKey,
+				guideWeightKey,
+			)
+		}
+	}
+	return nil
+}
+
+// unquoteYAML removes the quotes around a double or single quoted YAML
+// scalar. Other values are returned as they are.
+func unquoteYAML(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		if unquoted, unquoteErr := strconv.Unquote(value); unquoteErr == nil {
+			return unquoted
+		}
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1)
+	}
+	return value
+}
+
+// parseGuideTemplates parses the template files of the guides templates
+// directory into the template tree. Guides are parsed without their front
+// matter and named after the directory; other templates in the directory are
+// parsed like the rest of the templates directory.
+func parseGuideTemplates(t *template.Template, opts Options, matches []string) error {
+	others := []string{}
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".md"+opts.TemplateExt) {
+			others = append(others, match)
+			continue
+		}
+		g, guideErr := readGuide(opts, match)
+		if guideErr != nil {
+			return guideErr
+		}
+		if _, parseErr := t.New(g.template).Parse(g.body); parseErr != nil {
+			return parseErr
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	_, parseErr := t.ParseFiles(others...)
+	return parseErr
+}
+
+// newGuidePages returns the pages of the guides, in the order of the guides
+func newGuidePages(site siteGenerator, guides []guide) []sitePage {
+	pages := []sitePage{}
+	for _, g := range guides {
+		pagePath := site.pagePath(typeGuide, g.name)
+		pages = append(pages, sitePage{
+			Name:    g.title,
+			Path:    pagePath,
+			ID:      strings.TrimSuffix(pagePath, ".md"),
+			Summary: g.description,
+			Weight:  g.weight,
+		})
+	}
+	return pages
+}
+
+// newGuideData returns the template data of a guide page. References in the
+// descriptions of the provider arguments, and of the resources and data
+// sources, are linked relative to the guide.
+func newGuideData(provider *schema.Provider, opts Options, site siteGenerator, data siteData, g guide) guideDocData {
+	guideData := guideDocData{
+		Name:            g.name,
+		Title:           g.title,
+		Description:     g.description,
+		Weight:          g.weight,
+		Path:            site.pagePath(typeGuide, g.name),
+		ProviderName:    opts.ProviderName,
+		ProviderType:    providerType(provider),
+		Arguments:       schemaArguments(provider.Schema, ""),
+		Blocks:          schemaBlocks(provider.Schema, ""),
+		ResourcePages:   data.ResourcePages,
+		DataSourcePages: data.DataSourcePages,
+		Guides:          data.Guides,
+		Categories:      data.Categories,
+		Categorized:     data.Categorized,
+	}
+	sort.Slice(guideData.Arguments, func(i, j int) bool {
+		return guideData.Arguments[i].Name < guideData.Arguments[j].Name
+	})
+	guideData.FrontMatter = site.frontMatter(schemaDocData{
+		SchemaType:   typeGuide,
+		Name:         g.title,
+		ProviderName: opts.ProviderName,
+		Meta:         meta{Summary: g.description},
+	})
+	linkAttributeRefs(site, guideData, nil, guideData.Arguments)
+	linkBlockRefs(site, guideData, guideData.Blocks)
+	guideData.Resources = guideSchemaData(site, guideData, typeResource, provider.ResourcesMap)
+	guideData.DataSources = guideSchemaData(site, guideData, typeDataSource, provider.DataSourcesMap)
+	return guideData
+}
+
+// guideSchemaData returns the template data of each resource or data source
+// in the map, keyed by name, with the references in its descriptions linked
+// relative to the guide. Examples that cannot be generated are left empty;
+// they fail the generation of the resource page instead.
+func guideSchemaData(site siteGenerator, from guideDocData, schemaType int, resources map[string]*schema.Resource) map[string]schemaDocData {
+	data := map[string]schemaDocData{}
+	for name, resource := range resources {
+		resourceData := newSchemaDocData(schemaType, name, resource, resource.Schema)
+		resourceData.ProviderName = from.ProviderName
+		resourceData.ProviderType = from.ProviderType
+		resourceData.ExampleHCL, _ = exampleHCL(schemaType, name, resource.Schema)
+		resourceData.Meta.Summary = linkRefs(site, from, resourceData.Meta.Summary)
+		linkAttributeRefs(site, from, resourceData.Attributes, resourceData.Arguments)
+		linkBlockRefs(site, from, resourceData.Blocks)
+		data[name] = resourceData
+	}
+	return data
+}
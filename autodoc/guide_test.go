@@ -0,0 +1,195 @@
+package autodoc
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Guides
+// -----------------------------------------------------------------------------
+
+// guideTemplates returns a template set with two guides, out of order by
+// name
+func guideTemplates() map[string]string {
+	return map[string]string{
+		"guides/authentication.md.template": "---\n" +
+			"title: \"Authentication: Tokens\"\n" +
+			"weight: 20\n" +
+			"---\n" +
+			"{{.FrontMatter}}# {{.Title}}\n\n" +
+			"{{range .Arguments}}{{template \"_argument.template\" .}}{{end}}\n" +
+			"{{range .ResourcePages}}* {{resourceLink $ .Name}}\n{{end}}",
+		"guides/upgrading.md.template": "---\n" +
+			"title: Upgrading\n" +
+			"description: 'Upgrading to v2'\n" +
+			"weight: 10\n" +
+			"---\n" +
+			"# {{.Title}}\n" +
+			"{{with index .DataSources \"foo_baz\"}}\n## {{.Name}}\n\n" +
+			"{{range .Arguments}}{{if not .Optional}}* {{.Name}}: {{.Description}}\n{{end}}{{end}}" +
+			"{{range .Blocks}}* block {{.Name}}\n{{end}}{{end}}",
+	}
+}
+
+// providerGuides returns a provider with an argument and a data source
+// argument that reference a resource
+func providerGuides() *schema.Provider {
+	provider := providerFoo()
+	provider.DataSourcesMap["foo_baz"].Schema["name"].Description = "Name of the @REF(foo_bar)."
+	provider.Schema["token"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "Token to authenticate with. See @REF(foo_bar).",
+	}
+	return provider
+}
+
+// Ensures guide front matter is parsed and separated from the body
+func TestParseGuideFrontMatter(t *testing.T) {
+	g := guide{}
+	content := "---\ntitle: 'It''s here'\ndescription: \"A \\\"guide\\\"\"\nweight: -5\n---\n# Body\n"
+	if parseErr := parseGuideFrontMatter(&g, content); parseErr != nil {
+		t.Fatalf("parseGuideFrontMatter returned an error: [%s]", parseErr)
+	}
+	if g.title != "It's here" || g.description != `A "guide"` || g.weight != -5 || g.body != "# Body\n" {
+		t.Fatalf(
+			"parseGuideFrontMatter did not return the correct output. Got "+
+				"[%+v].",
+			g,
+		)
+	}
+
+	g = guide{}
+	if parseErr := parseGuideFrontMatter(&g, "# No front matter\n"); parseErr != nil || g.body != "# No front matter\n" {
+		t.Fatalf(
+			"parseGuideFrontMatter did not return the correct output. "+
+				"Expected the content as the body, got [%s] (%v).",
+			g.body,
+			parseErr,
+		)
+	}
+
+	cases := map[string]string{
+		"---\ntitle: A\n":            "Front matter is not terminated.",
+		"---\nweight: high\n---\n":   "Weight [high] is not an integer.",
+		"---\nsidebar: x\n---\n":     "Unknown front matter key [sidebar]. Expected one of [description, title, weight].",
+		"---\njust some text\n---\n": "Front matter line [just some text] is not a key: value pair.",
+	}
+	for content, expected := range cases {
+		actual := ""
+		if parseErr := parseGuideFrontMatter(&guide{}, content); parseErr != nil {
+			actual = parseErr.Error()
+		}
+		if actual != expected {
+			t.Fatalf(
+				"parseGuideFrontMatter did not return the correct error for "+
+					"[%s]. Expected [%s], got [%s].",
+				content,
+				expected,
+				actual,
+			)
+		}
+	}
+}
+
+// Ensures guides are rendered with the provider model and added to the
+// navigation in the order of their weight
+func TestDocumentWithOptions_Guides(t *testing.T) {
+	templatesDir := writeTemplates(t, guideTemplates())
+	defer os.RemoveAll(templatesDir)
+	for _, c := range []struct {
+		profile  string
+		path     string
+		expected string
+	}{
+		{
+			ProfileMkdocs,
+			"/out/mkdocs.yml",
+			"  - Home: 'index.md'\n  - Guides:\n" +
+				"    - \"Upgrading\": 'guides/upgrading.md'\n" +
+				"    - \"Authentication: Tokens\": 'guides/authentication.md'\n",
+		},
+		{
+			ProfileMkdocs,
+			"/out/docs/guides/authentication.md",
+			"# Authentication: Tokens\n\n" +
+				"* `token` - (Optional) `schema.TypeString`. Token to authenticate " +
+				"with. See [`foo_bar`](../resources/foo_bar.md).\n\n" +
+				"* [`foo_bar`](../resources/foo_bar.md)\n",
+		},
+		{
+			ProfileMkdocs,
+			"/out/docs/guides/upgrading.md",
+			"# Upgrading\n\n## foo_baz\n\n" +
+				"* name: Name of the [`foo_bar`](../resources/foo_bar.md).\n" +
+				"* block rule\n",
+		},
+		{
+			ProfileDocusaurus,
+			"/out/sidebars.js",
+			"    'index',\n    {\n      type: 'category',\n      label: 'Guides',\n" +
+				"      items: [\n        'guides/upgrading',\n        'guides/authentication',\n      ],\n",
+		},
+		{
+			ProfileDocusaurus,
+			"/out/docs/guides/authentication.md",
+			"---\ntitle: \"Authentication: Tokens\"\nsidebar_label: \"Authentication: Tokens\"\n---\n",
+		},
+		{
+			ProfileBare,
+			"/out/docs/README.md",
+			"## Guides\n\n* [Upgrading](guides/upgrading.md) - Upgrading to v2\n" +
+				"* [Authentication: Tokens](guides/authentication.md)\n\n## Resources\n",
+		},
+	} {
+		fs := NewMemoryFileSystem()
+		errs := DocumentWithOptions(providerGuides(), Options{
+			ProviderName: "terraform-provider-foo",
+			RootDir:      "/out",
+			DocsDir:      "/out/docs",
+			TemplatesDir: templatesDir,
+			Profile:      c.profile,
+			FileSystem:   fs,
+		})
+		if len(errs) != 0 {
+			t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+		}
+		content, _ := fs.ReadFile(c.path)
+		if !strings.Contains(string(content), c.expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output for "+
+					"[%s]. Expected [%s] in [%s]:\n%s",
+				c.profile,
+				c.expected,
+				c.path,
+				string(content),
+			)
+		}
+	}
+}
+
+// Ensures a guide with malformed front matter fails the generation
+func TestDocumentWithOptions_InvalidGuide(t *testing.T) {
+	templatesDir := writeTemplates(t, map[string]string{
+		"guides/broken.md.template": "---\nweight: first\n---\n# Broken\n",
+	})
+	defer os.RemoveAll(templatesDir)
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:      "/out",
+		TemplatesDir: templatesDir,
+		FileSystem:   NewMemoryFileSystem(),
+	})
+	expected := "broken.md.template]. Error: [Weight [first] is not an integer.]"
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Expected "+
+				"[%s], got %v.",
+			expected,
+			errs,
+		)
+	}
+}
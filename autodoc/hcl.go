@@ -0,0 +1,97 @@
+package autodoc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// HCL Utility Functions
+// -----------------------------------------------------------------------------
+
+// hclLiteral formats a Go value as an HCL literal expression (ie: the string
+// foo is formatted as "foo", a []string as ["a", "b"]). Maps are formatted as
+// objects with their keys sorted. nil is formatted as null.
+func hclLiteral(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return hclString(v.String())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		elems := []string{}
+		for i := 0; i < v.Len(); i++ {
+			elems = append(elems, hclLiteral(v.Index(i).Interface()))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case reflect.Map:
+		keys := []string{}
+		values := map[string]string{}
+		for _, key := range v.MapKeys() {
+			name := fmt.Sprint(key.Interface())
+			keys = append(keys, name)
+			values[name] = hclLiteral(v.MapIndex(key).Interface())
+		}
+		if len(keys) == 0 {
+			return "{}"
+		}
+		sort.Strings(keys)
+		attrs := []string{}
+		for _, key := range keys {
+			attrs = append(attrs, hclString(key)+" = "+values[key])
+		}
+		return "{ " + strings.Join(attrs, ", ") + " }"
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return "null"
+		}
+		return hclLiteral(v.Elem().Interface())
+	default:
+		return hclString(fmt.Sprint(value))
+	}
+}
+
+// hclString formats a string as a quoted HCL string literal. Quotes,
+// backslashes, and control characters are escaped, and template sequences
+// are escaped so the string is taken literally.
+func hclString(s string) string {
+	b := strings.Builder{}
+	b.WriteByte('"')
+	for idx, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			// "${" and "%{" begin template sequences, escaped by doubling
+			b.WriteRune(r)
+			if strings.HasPrefix(s[idx+1:], "{") {
+				b.WriteRune(r)
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
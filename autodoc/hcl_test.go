@@ -0,0 +1,38 @@
+package autodoc
+
+import (
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// HCL Literals
+// -----------------------------------------------------------------------------
+
+// Ensures Go values are formatted as HCL literal expressions
+func TestHCLLiteral(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "null"},
+		{"foo", `"foo"`},
+		{"say \"hi\"\n", `"say \"hi\"\n"`},
+		{"${var.foo} 100%", `"$${var.foo} 100%"`},
+		{true, "true"},
+		{10, "10"},
+		{1.5, "1.5"},
+		{[]string{"a", "b"}, `["a", "b"]`},
+		{[]interface{}{}, "[]"},
+		{map[string]interface{}{"b": 2, "a": "x"}, `{ "a" = "x", "b" = 2 }`},
+	}
+	for _, c := range cases {
+		if actual := hclLiteral(c.value); actual != c.expected {
+			t.Fatalf(
+				"hclLiteral did not return the correct output. Expected "+
+					"[%s], got [%s].",
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
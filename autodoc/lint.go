@@ -0,0 +1,410 @@
+package autodoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the lint rules, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// LintSeverity is the severity of a lint rule. Findings of error severity
+// fail the lint run.
+type LintSeverity string
+
+// Lint rule severities
+const (
+	// Findings fail the lint run
+	LintSeverityError LintSeverity = "error"
+	// Findings are reported but do not fail the lint run
+	LintSeverityWarning LintSeverity = "warning"
+	// The rule is disabled
+	LintSeverityOff LintSeverity = "off"
+)
+
+// Lint rule IDs. These are used to configure the severity of a rule and to
+// suppress a rule for an attribute with the @NOLINT metadata tag.
+const (
+	// An attribute has no description once metadata tags are removed
+	LintEmptyDescription = "empty-description"
+	// @EXAMPLE is set on a Computed-only attribute, which cannot be given a
+	// value in the config
+	LintExampleOnComputed = "example-on-computed"
+	// The meta attribute is not Computed, so it would be accepted as an
+	// argument in the config
+	LintMetaNotComputed = "meta-not-computed"
+	// A description contains a tag that is not a known metadata tag
+	LintUnknownTag = "unknown-tag"
+	// A resource or data source has no Description or @SUMMARY
+	LintMissingSummary = "missing-summary"
+	// ConflictsWith names an attribute that does not exist
+	LintUnknownConflictsWith = "unknown-conflicts-with"
+	// The metadata tags of a description cannot be parsed (ie: an
+	// unterminated quoted value), or a custom tag is used outside of its
+	// scope or with an invalid value
+	LintInvalidTag = "invalid-tag"
+	// A tag that accepts a single value is repeated in a description. Only
+	// its first value is used.
+	LintRepeatedTag = "repeated-tag"
+	// A @REF reference in a description cannot be parsed, or names a
+	// resource, data source, or attribute that does not exist
+	LintInvalidRef = "invalid-ref"
+)
+
+// Lint output formats
+const (
+	// Human readable text, one finding per line
+	LintFormatText = "text"
+	// A single JSON document
+	LintFormatJSON = "json"
+)
+
+// A lint rule and its default severity
+type lintRule struct {
+	// ID of the rule
+	id string
+	// Severity of the rule unless configured otherwise
+	severity LintSeverity
+}
+
+// All lint rules, in the order they are documented
+var lintRules = []lintRule{
+	{LintEmptyDescription, LintSeverityWarning},
+	{LintExampleOnComputed, LintSeverityWarning},
+	{LintMetaNotComputed, LintSeverityError},
+	{LintUnknownTag, LintSeverityError},
+	{LintMissingSummary, LintSeverityWarning},
+	{LintUnknownConflictsWith, LintSeverityError},
+	{LintInvalidTag, LintSeverityError},
+	{LintInvalidRef, LintSeverityError},
+	{LintRepeatedTag, LintSeverityWarning},
+}
+
+// -----------------------------------------------------------------------------
+// Lint Data Structs
+// -----------------------------------------------------------------------------
+
+// A problem found in the schema documentation
+type lintFinding struct {
+	// ID of the rule that produced the finding
+	Rule string `json:"rule"`
+	// Severity of the rule
+	Severity LintSeverity `json:"severity"`
+	// Name of the provider, resource, or data source
+	Object string `json:"object"`
+	// Dot separated path of the attribute. Empty for findings about the
+	// object itself.
+	Path string `json:"path,omitempty"`
+	// Description of the problem
+	Message string `json:"message"`
+}
+
+// The JSON lint report
+type lintReport struct {
+	// All findings, sorted by object, path, and rule
+	Findings []lintFinding `json:"findings"`
+	// Number of error severity findings
+	Errors int `json:"errors"`
+	// Number of warning severity findings
+	Warnings int `json:"warnings"`
+}
+
+// Collects the findings of a lint run
+type linter struct {
+	// Severity of each rule by ID
+	severities map[string]LintSeverity
+	// The provider being linted, to check the references in descriptions
+	provider *schema.Provider
+	// Findings reported so far
+	findings []lintFinding
+}
+
+// -----------------------------------------------------------------------------
+// Lint Utility Functions
+// -----------------------------------------------------------------------------
+
+// lintProvider lints the schema documentation of the provider and all of its
+// resources and data sources, and writes the report to the options' output
+// in the configured format. Returns a list of errors. If this list is empty,
+// no error severity findings were reported.
+func lintProvider(provider *schema.Provider, opts Options) []error {
+	l, lintErr := newLinter(opts.LintRules)
+	if lintErr != nil {
+		return []error{lintErr}
+	}
+	l.provider = provider
+
+	l.lintSchema(typeProvider, opts.ProviderName, nil, provider.Schema)
+	for name, resource := range provider.ResourcesMap {
+		l.lintSchema(typeResource, name, resource, resource.Schema)
+	}
+	for name, resource := range provider.DataSourcesMap {
+		l.lintSchema(typeDataSource, name, resource, resource.Schema)
+	}
+
+	report := l.report()
+	switch opts.LintFormat {
+	case LintFormatJSON:
+		encoder := json.NewEncoder(opts.Out)
+		encoder.SetIndent("", "  ")
+		if encodeErr := encoder.Encode(report); encodeErr != nil {
+			return []error{encodeErr}
+		}
+	case LintFormatText, "":
+		for _, f := range report.Findings {
+			object := f.Object
+			if f.Path != "" {
+				object += "." + f.Path
+			}
+			fmt.Fprintf(opts.Out, "%s: %s: %s [%s]\n", object, f.Severity, f.Message, f.Rule)
+		}
+	default:
+		return []error{fmt.Errorf(
+			"Unrecognized lint format [%s]. Expected [%s] or [%s].",
+			opts.LintFormat,
+			LintFormatText,
+			LintFormatJSON,
+		)}
+	}
+
+	if report.Errors > 0 {
+		return []error{fmt.Errorf(
+			"Lint failed with [%d] error(s) and [%d] warning(s).",
+			report.Errors,
+			report.Warnings,
+		)}
+	}
+	return []error{}
+}
+
+// newLinter returns a linter with the default rule severities over-ridden
+// by the supplied severities. An error is returned if an unknown rule or
+// severity is configured.
+func newLinter(overrides map[string]LintSeverity) (*linter, error) {
+	l := &linter{
+		severities: map[string]LintSeverity{},
+		findings:   []lintFinding{},
+	}
+	for _, rule := range lintRules {
+		l.severities[rule.id] = rule.severity
+	}
+	for id, severity := range overrides {
+		if _, ok := l.severities[id]; !ok {
+			return nil, fmt.Errorf("Unrecognized lint rule [%s].", id)
+		}
+		switch severity {
+		case LintSeverityError, LintSeverityWarning, LintSeverityOff:
+			l.severities[id] = severity
+		default:
+			return nil, fmt.Errorf(
+				"Unrecognized severity [%s] for lint rule [%s].",
+				severity,
+				id,
+			)
+		}
+	}
+	return l, nil
+}
+
+// lintSchema lints the schema map of a provider, resource, or data source.
+// schemaType should be one of the typeXxx constants. resource is nil for the
+// provider.
+func (l *linter) lintSchema(schemaType int, object string, resource *schema.Resource, schemaMap map[string]*schema.Schema) {
+	metaDescr := ""
+	if metaSchema, ok := schemaMap[MetaAttribute]; ok {
+		metaDescr = metaSchema.Description
+		if !metaSchema.Computed {
+			l.add(
+				LintMetaNotComputed, object, "",
+				resourceLintDescr(resource, metaDescr, LintMetaNotComputed),
+				"The meta attribute must be Computed.",
+			)
+		}
+		l.lintTags(object, "", metaDescr, TagScopeResource)
+	}
+	if resource != nil {
+		l.lintTags(object, "", resource.Description, TagScopeResource)
+	}
+	if schemaType != typeProvider && parseMeta(schemaType, resource, schemaMap).Summary == "" {
+		l.add(
+			LintMissingSummary, object, "",
+			resourceLintDescr(resource, metaDescr, LintMissingSummary),
+			fmt.Sprintf(
+				"The resource has no Description and no %s tag.",
+				MetaSummary,
+			),
+		)
+	}
+
+	walkSchema(schemaMap, "", func(path string, s *schema.Schema) {
+		if path == MetaAttribute {
+			return
+		}
+		if stripMeta(s.Description) == "" {
+			l.add(
+				LintEmptyDescription, object, path, s.Description,
+				"The description is empty.",
+			)
+		}
+		if s.Computed && !s.Optional && hasMetaTag(s.Description, MetaExample) {
+			l.add(
+				LintExampleOnComputed, object, path, s.Description,
+				fmt.Sprintf(
+					"%s is set on a Computed-only attribute, which cannot be "+
+						"set in the config.",
+					MetaExample,
+				),
+			)
+		}
+		for _, conflict := range s.ConflictsWith {
+			if !schemaPathExists(schemaMap, conflict) {
+				l.add(
+					LintUnknownConflictsWith, object, path, s.Description,
+					fmt.Sprintf(
+						"ConflictsWith names attribute [%s], which does not "+
+							"exist.",
+						conflict,
+					),
+				)
+			}
+		}
+		l.lintTags(object, path, s.Description, TagScopeAttribute)
+	})
+}
+
+// lintTags reports every tag in the description that is not a known metadata
+// tag, and the description if its tags cannot be parsed, a custom tag is
+// used outside of its scope or with an invalid value, or a reference is
+// invalid
+func (l *linter) lintTags(object string, path string, descr string, scope TagScope) {
+	parsed, parseErr := parseScopedDescription(descr, scope)
+	for _, tag := range parsed.unknown {
+		l.add(
+			LintUnknownTag, object, path, descr,
+			fmt.Sprintf("Unrecognized metadata tag [%s].", tag),
+		)
+	}
+	for _, tag := range parsed.repeated {
+		l.add(
+			LintRepeatedTag, object, path, descr,
+			fmt.Sprintf(
+				"Tag [%s] is repeated. It accepts a single value; only the "+
+					"first one is used.",
+				tag,
+			),
+		)
+	}
+	if parseErr != nil {
+		l.add(LintInvalidTag, object, path, descr, parseErr.Error())
+	}
+	if l.provider != nil {
+		if refErr := checkRefs(l.provider, descr); refErr != nil {
+			l.add(LintInvalidRef, object, path, descr, refErr.Error())
+		}
+	}
+}
+
+// add records a finding for the rule unless the rule is disabled or
+// suppressed by a @NOLINT tag in the description
+func (l *linter) add(rule string, object string, path string, descr string, message string) {
+	severity := l.severities[rule]
+	if severity == LintSeverityOff || lintSuppressed(descr, rule) {
+		return
+	}
+	l.findings = append(l.findings, lintFinding{
+		Rule:     rule,
+		Severity: severity,
+		Object:   object,
+		Path:     path,
+		Message:  message,
+	})
+}
+
+// report returns the sorted findings and their counts
+func (l *linter) report() lintReport {
+	sort.Slice(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.Object != b.Object {
+			return a.Object < b.Object
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Rule < b.Rule
+	})
+	report := lintReport{Findings: l.findings}
+	for _, f := range l.findings {
+		if f.Severity == LintSeverityError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+	return report
+}
+
+// resourceLintDescr returns the description whose @NOLINT tags suppress a
+// resource-level finding of the rule: the resource's Description if it
+// suppresses the rule, and the description of the meta attribute otherwise.
+// resource is nil for the provider.
+func resourceLintDescr(resource *schema.Resource, metaDescr string, rule string) string {
+	if resource != nil && lintSuppressed(resource.Description, rule) {
+		return resource.Description
+	}
+	return metaDescr
+}
+
+// lintSuppressed returns whether the @NOLINT tags of the description
+// suppress the rule. A @NOLINT tag without a value suppresses all rules.
+func lintSuppressed(descr string, rule string) bool {
+	parsed, _ := parseDescription(descr)
+	for _, value := range parsed.values(MetaNoLint) {
+		if value == "" {
+			return true
+		}
+		for _, id := range strings.Split(value, ",") {
+			if strings.TrimSpace(id) == rule {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaPathExists returns whether the attribute at the path exists in the
+// schema map. Paths are in the format used by ConflictsWith, where the
+// elements of a nested block are addressed by index (ie: "rule.0.priority").
+func schemaPathExists(schemaMap map[string]*schema.Schema, path string) bool {
+	current := schemaMap
+	parts := strings.Split(path, ".")
+	for idx := 0; idx < len(parts); idx++ {
+		attrSchema, ok := current[parts[idx]]
+		if !ok {
+			return false
+		}
+		if idx == len(parts)-1 {
+			return true
+		}
+		if !isBlock(attrSchema) {
+			return false
+		}
+		current = attrSchema.Elem.(*schema.Resource).Schema
+		// skip the block index
+		if _, numErr := strconv.Atoi(parts[idx+1]); numErr == nil {
+			idx++
+			if idx == len(parts)-1 {
+				return true
+			}
+		}
+	}
+	return false
+}
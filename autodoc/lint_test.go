@@ -0,0 +1,214 @@
+package autodoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Test Helpers
+// -----------------------------------------------------------------------------
+
+func providerLint() *schema.Provider {
+	return &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"foo_bar": &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					MetaAttribute: &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "@SUMARY Misspelled summary",
+					},
+					"id_out": &schema.Schema{
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "Generated ID @EXAMPLE abc",
+					},
+					"name": &schema.Schema{
+						Type:          schema.TypeString,
+						Optional:      true,
+						ConflictsWith: []string{"rule.0.priority", "bogus"},
+					},
+					"quiet": &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "@NOLINT empty-description",
+					},
+					"rule": &schema.Schema{
+						Type:        schema.TypeList,
+						Optional:    true,
+						Description: "Rules",
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"priority": &schema.Schema{
+									Type:        schema.TypeInt,
+									Optional:    true,
+									Description: "Priority",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// lintProvider
+// -----------------------------------------------------------------------------
+
+// Ensures each rule reports its finding, and suppressed findings are skipped
+func TestLintProvider(t *testing.T) {
+	out := bytes.Buffer{}
+	errs := lintProvider(providerLint(), Options{
+		LintFormat: LintFormatJSON,
+		Out:        &out,
+	})
+	if len(errs) != 1 {
+		t.Fatalf("lintProvider did not fail on error severity findings")
+	}
+
+	report := lintReport{}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("lintProvider did not write valid JSON: [%s]", err)
+	}
+	expected := []lintFinding{
+		{LintMetaNotComputed, LintSeverityError, "foo_bar", "", ""},
+		{LintMissingSummary, LintSeverityWarning, "foo_bar", "", ""},
+		{LintUnknownTag, LintSeverityError, "foo_bar", "", ""},
+		{LintExampleOnComputed, LintSeverityWarning, "foo_bar", "id_out", ""},
+		{LintEmptyDescription, LintSeverityWarning, "foo_bar", "name", ""},
+		{LintUnknownConflictsWith, LintSeverityError, "foo_bar", "name", ""},
+	}
+	if len(report.Findings) != len(expected) {
+		t.Fatalf(
+			"lintProvider did not return the correct output. Expected [%d] "+
+				"findings, got %+v.",
+			len(expected),
+			report.Findings,
+		)
+	}
+	for idx, finding := range report.Findings {
+		finding.Message = ""
+		if finding != expected[idx] {
+			t.Fatalf(
+				"lintProvider did not return the correct output. Expected "+
+					"finding [%+v], got [%+v].",
+				expected[idx],
+				finding,
+			)
+		}
+	}
+	if report.Errors != 3 || report.Warnings != 3 {
+		t.Fatalf(
+			"lintProvider did not return the correct output. Expected [3] "+
+				"errors and [3] warnings, got [%d] and [%d].",
+			report.Errors,
+			report.Warnings,
+		)
+	}
+}
+
+// Ensures rule severities can be configured
+func TestLintProvider_Rules(t *testing.T) {
+	out := bytes.Buffer{}
+	errs := lintProvider(providerLint(), Options{
+		LintFormat: LintFormatText,
+		LintRules: map[string]LintSeverity{
+			LintMetaNotComputed:      LintSeverityOff,
+			LintUnknownTag:           LintSeverityWarning,
+			LintUnknownConflictsWith: LintSeverityWarning,
+		},
+		Out: &out,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("lintProvider returned errors: %v\n%s", errs, out.String())
+	}
+
+	errs = lintProvider(providerLint(), Options{
+		LintRules: map[string]LintSeverity{"bogus": LintSeverityOff},
+		Out:       &out,
+	})
+	if len(errs) != 1 {
+		t.Fatalf("lintProvider did not return an error for an unknown rule")
+	}
+}
+
+// Ensures resource-level findings are suppressed by a @NOLINT tag in the
+// resource's Description, without a meta attribute
+func TestLintProvider_ResourceNoLint(t *testing.T) {
+	out := bytes.Buffer{}
+	provider := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"foo_bar": &schema.Resource{
+				Description: "@NOLINT missing-summary",
+				Schema: map[string]*schema.Schema{
+					"name": &schema.Schema{
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "Name",
+					},
+				},
+			},
+		},
+	}
+	errs := lintProvider(provider, Options{LintFormat: LintFormatJSON, Out: &out})
+	if len(errs) != 0 {
+		t.Fatalf("lintProvider returned errors: %v", errs)
+	}
+	report := lintReport{}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("lintProvider did not write valid JSON: [%s]", err)
+	}
+	if len(report.Findings) != 0 {
+		t.Fatalf(
+			"lintProvider did not return the correct output. Expected no "+
+				"findings, got %+v.",
+			report.Findings,
+		)
+	}
+}
+
+// Ensures a repeated single value tag is reported as a warning and does not
+// fail the generation
+func TestLintProvider_RepeatedTag(t *testing.T) {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Schema["name"].Description = "Name @EXAMPLE bar @EXAMPLE baz"
+
+	out := bytes.Buffer{}
+	errs := lintProvider(provider, Options{LintFormat: LintFormatJSON, Out: &out})
+	if len(errs) != 0 {
+		t.Fatalf("lintProvider returned errors: %v", errs)
+	}
+	report := lintReport{}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("lintProvider did not write valid JSON: [%s]", err)
+	}
+	found := false
+	for _, finding := range report.Findings {
+		if finding.Rule == LintRepeatedTag && finding.Object == "foo_bar" && finding.Path == "name" {
+			found = finding.Severity == LintSeverityWarning
+		}
+	}
+	if !found {
+		t.Fatalf(
+			"lintProvider did not return the correct output. Expected a "+
+				"[%s] warning for [foo_bar.name], got %+v.",
+			LintRepeatedTag,
+			report.Findings,
+		)
+	}
+
+	errs = DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   NewMemoryFileSystem(),
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+}
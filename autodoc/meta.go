@@ -1,7 +1,8 @@
 package autodoc
 
 import (
-	"strings"
+	"fmt"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -20,6 +21,10 @@ const (
 	// The meta attribute. If an attribute has this name, autodoc will interpret
 	// it as a special tag and parses its description for more resource-level
 	// metadata information. This attribute is never included in the docs.
+	//
+	// Deprecated: resource-level metadata is read from the schema.Resource
+	// (Description, DeprecationMessage, and the CRUD functions). The meta
+	// attribute is only supported as a backwards compatible over-ride.
 	MetaAttribute = "__meta__"
 	// Metadata tag to signal that this resource cannot be created. This should
 	// be in the description of the meta attribute. This tag does
@@ -50,8 +55,95 @@ const (
 	// to assume the attribute is exported. This will over-ride that behavior.
 	// This tag does not accept a value.
 	MetaUnexported = "@UNEXPORTED"
+	// Metadata tag to suppress lint findings for an attribute. This tag
+	// accepts a comma separated list of the lint rule IDs to suppress. If no
+	// value is given, all lint rules are suppressed for the attribute.
+	// Resource-level rules are suppressed in the resource's Description or
+	// in the description of the meta attribute.
+	MetaNoLint = "@NOLINT"
+	// Metadata tag to describe the ID used to import the resource (ie:
+	// "<region>/<name>"). This should be in the resource's Description or in
+	// the description of the meta attribute. This tag accepts a value
+	// corresponding to the format of the import ID. The default behavior
+	// assumes the resource is imported by its ID.
+	MetaImportID = "@IMPORT_ID"
+	// Metadata tag to assign a resource or data source to a named category
+	// (ie: "Networking"). This should be in the resource's Description or in
+	// the description of the meta attribute. This tag accepts a value
+	// corresponding to the name of the category. Resources without a
+	// category are listed under "Uncategorized".
+	MetaCategory = "@CATEGORY"
+)
+
+// Category of the resources and data sources without a @CATEGORY tag
+const uncategorized = "Uncategorized"
+
+// TagScope is a set of places a custom metadata tag can be used. Scopes can
+// be combined with '|'.
+type TagScope int
+
+// Custom metadata tag scopes
+const (
+	// The description of the resource or data source, or of the meta
+	// attribute
+	TagScopeResource TagScope = 1 << iota
+	// The description of an attribute or nested block
+	TagScopeAttribute
 )
 
+// TagDefinition defines a custom metadata tag. Custom tags are parsed and
+// stripped from descriptions like the built-in tags, and their values are
+// exposed to templates in the Custom map of the resource metadata, arguments,
+// and attributes.
+type TagDefinition struct {
+	// Name of the tag, including the '@' (ie: "@SINCE"). The name is an '@'
+	// followed by upper case letters, digits, and underscores.
+	Name string
+	// Whether or not the tag is followed by a value (ie: "@SINCE 2.3.0").
+	HasValue bool
+	// Where the tag can be used. Using the tag anywhere else is an error.
+	Scope TagScope
+	// Validates the value of the tag. Optional. Called with the empty string
+	// for tags without a value.
+	Validate func(value string) error
+}
+
+// Definition of a metadata tag
+type metaTagDef struct {
+	// Name of the tag, including the '@'
+	name string
+	// Whether or not the tag is followed by a value
+	hasValue bool
+	// Whether or not the tag can appear more than once in a description. The
+	// values of every occurrence are kept.
+	repeatable bool
+	// Whether or not the tag was registered with RegisterTag
+	custom bool
+	// Where a custom tag can be used
+	scope TagScope
+	// Validates the value of a custom tag. Optional.
+	validate func(value string) error
+}
+
+// Guards the metadata tag registry, so tags can be registered while
+// documentation is generated
+var metaTagRegistryLock sync.RWMutex
+
+// Registry of all known metadata tags. The tokenizer only parses the tags in
+// this list; anything else that looks like a tag is left in the description.
+// Guarded by metaTagRegistryLock.
+var metaTagRegistry = []metaTagDef{
+	{name: MetaNotCreatable, repeatable: true},
+	{name: MetaNotDeletable, repeatable: true},
+	{name: MetaImmutable, repeatable: true},
+	{name: MetaSummary, hasValue: true},
+	{name: MetaExample, hasValue: true},
+	{name: MetaUnexported, repeatable: true},
+	{name: MetaNoLint, hasValue: true, repeatable: true},
+	{name: MetaImportID, hasValue: true},
+	{name: MetaCategory, hasValue: true},
+}
+
 // -----------------------------------------------------------------------------
 // Metadata Definition
 // -----------------------------------------------------------------------------
@@ -71,147 +163,161 @@ type meta struct {
 	Immutable bool
 	// Summary of the resource
 	Summary string
+	// Deprecation message of the resource. Empty if it is not deprecated.
+	Deprecated string
+	// Category of the resource, from the @CATEGORY tag. Empty for the
+	// provider and for resources without a category.
+	Category string
+	// Values of the custom tags of the resource by tag name, without the '@'
+	// (ie: "SINCE"). Tags without a value are set to "true".
+	Custom map[string]string
 }
 
 // -----------------------------------------------------------------------------
 // Metadata Utility Functions
 // -----------------------------------------------------------------------------
 
-// parseMeta parses the metadata from the resource map. The metadata is defined
-// in the metadata attribute's description.
-func parseMeta(schemaMap map[string]*schema.Schema) meta {
-	meta := meta{}
-	for attrName, attrSchema := range schemaMap {
-		if attrName == MetaAttribute {
-			meta.Uncreatable = strings.Contains(attrSchema.Description, MetaNotCreatable)
-			meta.Undeletable = strings.Contains(attrSchema.Description, MetaNotDeletable)
-			meta.Immutable = strings.Contains(attrSchema.Description, MetaImmutable)
-			meta.Summary = parseMetaValue(attrSchema.Description, MetaSummary)
-			break
+// parseMeta parses the metadata of a provider, resource, or data source.
+// schemaType should be one of the typeXxx constants. resource is nil for the
+// provider.
+//
+// The metadata is read from the resource definition: the summary from its
+// Description, the deprecation from its DeprecationMessage, and, for
+// resources, whether or not it can be created, updated, or deleted from its
+// CRUD functions. The meta attribute is still supported for backwards
+// compatibility; its tags over-ride the resource definition.
+func parseMeta(schemaType int, resource *schema.Resource, schemaMap map[string]*schema.Schema) meta {
+	meta := meta{Custom: map[string]string{}}
+	if resource != nil {
+		parsed, _ := parseDescription(resource.Description)
+		meta.Summary = parsed.text
+		meta.Category = parsed.value(MetaCategory)
+		meta.Custom = customTags(parsed)
+		meta.Deprecated = resource.DeprecationMessage
+		if schemaType == typeResource {
+			meta.Uncreatable = resource.Create == nil && resource.CreateContext == nil
+			meta.Immutable = resource.Update == nil && resource.UpdateContext == nil
+			meta.Undeletable = resource.Delete == nil && resource.DeleteContext == nil
+		}
+	}
+	if attrSchema, ok := schemaMap[MetaAttribute]; ok {
+		parsed, _ := parseDescription(attrSchema.Description)
+		meta.Uncreatable = meta.Uncreatable || parsed.has(MetaNotCreatable)
+		meta.Undeletable = meta.Undeletable || parsed.has(MetaNotDeletable)
+		meta.Immutable = meta.Immutable || parsed.has(MetaImmutable)
+		if summary := parsed.value(MetaSummary); summary != "" {
+			meta.Summary = summary
+		}
+		if category := parsed.value(MetaCategory); category != "" {
+			meta.Category = category
+		}
+		for name, value := range customTags(parsed) {
+			meta.Custom[name] = value
 		}
 	}
 	return meta
 }
 
-// parseMetaValue parses a schema description string for metadata tag
-// and returns the value associated with that tag.
-func parseMetaValue(descr string, metaTag string) string {
-	value := ""
-
-	// find where the meta tag exists in the description string. If the meta
-	// tag cannot be found, return
-	metaTagIdx := strings.Index(descr, metaTag)
-	if metaTagIdx < 0 {
-		return value
+// parseImportID parses the format of the ID used to import the resource from
+// the @IMPORT_ID tag. The tag in the description of the meta attribute
+// over-rides the tag in the resource's Description. The empty string is
+// returned if neither has the tag.
+func parseImportID(resource *schema.Resource, schemaMap map[string]*schema.Schema) string {
+	if attrSchema, ok := schemaMap[MetaAttribute]; ok {
+		if format := parseMetaValue(attrSchema.Description, MetaImportID); format != "" {
+			return format
+		}
 	}
-
-	// take a substring of the description starting with the end of the
-	// meta tag. This is our known metadata value
-	value = descr[metaTagIdx+len(metaTag):]
-	// value is the empty string, return
-	valueLen := len(value)
-	if valueLen == 0 {
-		return value
+	if resource == nil {
+		return ""
 	}
+	return parseMetaValue(resource.Description, MetaImportID)
+}
 
-	// Retrieve the ending location of the value. This is either the index of
-	// next meta tag, or the end of the string
-	var valueEndIdx int
-	if valueEndIdx = nextMetaTagIndex(value); valueEndIdx < 0 {
-		valueEndIdx = valueLen - 1
+// RegisterTag registers a custom metadata tag. Tags should be registered
+// before calling Document or DocumentWithOptions; RegisterTag is safe to call
+// concurrently, but a tag registered during a run may not be parsed on every
+// page. An error is returned if the name is not a valid tag name, the tag is
+// already registered (including the built-in tags), or no scope is given.
+func RegisterTag(def TagDefinition) error {
+	if def.Name == "" || def.Name[0] != metaTagPrefix || metaTagWord(def.Name, 0) != def.Name {
+		return fmt.Errorf(
+			"Cannot register tag [%s]. Tag names are an '@' followed by upper "+
+				"case letters, digits, and underscores.",
+			def.Name,
+		)
+	}
+	if def.Scope&(TagScopeResource|TagScopeAttribute) == 0 {
+		return fmt.Errorf("Cannot register tag [%s]. It has no scope.", def.Name)
 	}
 
-	// The value text will be the substring to this index. Trim any
-	// leading and trailing whitespace.
-	//
-	// If the value goes to the end of the string, add 1 to the end index when
-	// taking the substring. The index operator [:idx] is exclusive on the right
-	// bound. We don't want to miss this character at the end of the string.  In
-	// the cases where we don't hit the end of the string, we can just go to
-	// valueEndIdx. If we were to add 1 in this case, we'd include the '@' of
-	// the next tag.
-	if valueEndIdx == valueLen-1 {
-		return strings.TrimSpace(value[:valueEndIdx+1])
+	metaTagRegistryLock.Lock()
+	defer metaTagRegistryLock.Unlock()
+	if _, ok := findMetaTag(def.Name); ok {
+		return fmt.Errorf("Cannot register tag [%s]. It is already registered.", def.Name)
 	}
-	return strings.TrimSpace(value[:valueEndIdx])
+	metaTagRegistry = append(metaTagRegistry, metaTagDef{
+		name:     def.Name,
+		hasValue: def.HasValue,
+		custom:   true,
+		scope:    def.Scope,
+		validate: def.Validate,
+	})
+	return nil
 }
 
-// nextMetaTagIndex returns the character index of the first encountered
-// meta tag. -1 is returned if no valid meta tags are found.
-func nextMetaTagIndex(value string) int {
-	// Assume we are not going to encounter another meta tag and will run
-	// to the end of the string
-	valueLen := len(value) - 1
-	valueEndIdx := valueLen
-	// Move the index back if we encounter another metadata tag. We want the
-	// text immediately following the summary tag up to the next metadata tag
-	metaTags := []string{
-		MetaNotCreatable,
-		MetaNotDeletable,
-		MetaImmutable,
-		MetaSummary,
-		MetaExample,
-		MetaUnexported,
-	}
-	for _, tag := range metaTags {
-		if endIdx := strings.Index(value, tag); endIdx != -1 && endIdx < valueEndIdx {
-			valueEndIdx = endIdx
+// lookupMetaTag returns the definition of a registered metadata tag
+func lookupMetaTag(name string) (metaTagDef, bool) {
+	metaTagRegistryLock.RLock()
+	defer metaTagRegistryLock.RUnlock()
+	return findMetaTag(name)
+}
+
+// findMetaTag returns the definition of a registered metadata tag. The
+// caller holds metaTagRegistryLock.
+func findMetaTag(name string) (metaTagDef, bool) {
+	for _, def := range metaTagRegistry {
+		if def.name == name {
+			return def, true
 		}
 	}
-	// No other meta tags found
-	if valueEndIdx == valueLen {
-		return -1
-	}
-	// Hit another tag, give its position
-	return valueEndIdx
+	return metaTagDef{}, false
 }
 
-// stripMeta removes any metadata tags from a schema description and their
-// associated values.
-func stripMeta(descr string) string {
-	descrCleaned := descr
-
-	// for meta tags that do not accept a value, just remove them
-	metaTagsNoValue := []string{
-		MetaNotCreatable,
-		MetaNotDeletable,
-		MetaImmutable,
-		MetaUnexported,
-	}
-	for _, tag := range metaTagsNoValue {
-		descrCleaned = strings.Replace(descrCleaned, tag, "", 1)
-	}
+// hasMetaTag returns whether or not a schema description has the metadata
+// tag
+func hasMetaTag(descr string, metaTag string) bool {
+	parsed, _ := parseDescription(descr)
+	return parsed.has(metaTag)
+}
 
-	// for meta tags that accept a value, remove the tag and its value
-	metaTagsValue := []string{
-		MetaSummary,
-		MetaExample,
-	}
-	for _, tag := range metaTagsValue {
-		tagLen := len(tag)
-		metaBeginIdx := strings.Index(descrCleaned, tag)
-		// tag not found - skip
-		if metaBeginIdx < 0 {
-			continue
-		}
-		// starting at the end of tag, search for start of another tag
-		metaEndIdx := nextMetaTagIndex(descrCleaned[metaBeginIdx+tagLen:])
-		// Meta value goes to the end of the string. Create a substring
-		// to the beginning of the meta tag - effectively cutting the meta
-		// tag and value out
-		if metaEndIdx < 0 {
-			descrCleaned = descrCleaned[:metaBeginIdx]
-			continue
+// parseMetaValue parses a schema description string for metadata tag
+// and returns the value associated with that tag. Descriptions that cannot
+// be parsed are parsed as far as possible; see validateMetaTags.
+func parseMetaValue(descr string, metaTag string) string {
+	parsed, _ := parseDescription(descr)
+	return parsed.value(metaTag)
+}
+
+// customTags returns the values of the custom tags of a parsed description
+// by tag name, without the '@'. Tags without a value are set to "true".
+func customTags(parsed parsedDescription) map[string]string {
+	custom := map[string]string{}
+	for _, token := range parsed.tags {
+		if def, _ := lookupMetaTag(token.name); def.custom {
+			value := token.value
+			if !def.hasValue {
+				value = "true"
+			}
+			custom[token.name[1:]] = value
 		}
-		// offset the end index - the next meta tag index was calculated from the
-		// value of the tag
-		metaEndIdx += metaBeginIdx + tagLen
-		// Meta value is somewhere inside the string. Concatenate the string
-		// to the beginning of the meta tag with the string starting with the
-		// end of the meta tag value - effectively cutting the meta tag and
-		// value out.
-		descrCleaned = descrCleaned[:metaBeginIdx] + descrCleaned[metaEndIdx:]
 	}
+	return custom
+}
 
-	return strings.TrimSpace(descrCleaned)
+// stripMeta removes any metadata tags from a schema description and their
+// associated values.
+func stripMeta(descr string) string {
+	parsed, _ := parseDescription(descr)
+	return parsed.text
 }
@@ -0,0 +1,239 @@
+package autodoc
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// parseMeta
+// -----------------------------------------------------------------------------
+
+// Ensures the metadata is read from the resource definition, and the meta
+// attribute over-rides it
+func TestParseMeta(t *testing.T) {
+	crud := func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return nil
+	}
+	resource := &schema.Resource{
+		Description:        "A foo. @CATEGORY Compute",
+		DeprecationMessage: "Use foo_qux instead.",
+		CreateContext:      crud,
+		ReadContext:        crud,
+		DeleteContext:      crud,
+		Schema:             map[string]*schema.Schema{},
+	}
+
+	expected := meta{
+		Immutable:  true,
+		Summary:    "A foo.",
+		Deprecated: "Use foo_qux instead.",
+		Category:   "Compute",
+		Custom:     map[string]string{},
+	}
+	if actual := parseMeta(typeResource, resource, resource.Schema); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"parseMeta did not return the correct output. Expected [%+v], "+
+				"got [%+v].",
+			expected,
+			actual,
+		)
+	}
+
+	// data sources have no create, update, or delete
+	expected.Immutable = false
+	if actual := parseMeta(typeDataSource, resource, resource.Schema); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"parseMeta did not return the correct output. Expected [%+v], "+
+				"got [%+v].",
+			expected,
+			actual,
+		)
+	}
+
+	resource.Schema[MetaAttribute] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "@SUMMARY Over-ridden. @NOTDELETABLE @CATEGORY Storage",
+	}
+	expected = meta{
+		Immutable:   true,
+		Undeletable: true,
+		Summary:     "Over-ridden.",
+		Deprecated:  "Use foo_qux instead.",
+		Category:    "Storage",
+		Custom:      map[string]string{},
+	}
+	if actual := parseMeta(typeResource, resource, resource.Schema); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"parseMeta did not return the correct output. Expected [%+v], "+
+				"got [%+v].",
+			expected,
+			actual,
+		)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// RegisterTag
+// -----------------------------------------------------------------------------
+
+// registerTestTags registers custom tags for a test and returns a function
+// that restores the tag registry
+func registerTestTags(t *testing.T, defs ...TagDefinition) func() {
+	metaTagRegistryLock.RLock()
+	registry := metaTagRegistry
+	metaTagRegistryLock.RUnlock()
+	for _, def := range defs {
+		if err := RegisterTag(def); err != nil {
+			t.Fatalf("RegisterTag returned an error: [%s]", err)
+		}
+	}
+	return func() {
+		metaTagRegistryLock.Lock()
+		metaTagRegistry = registry
+		metaTagRegistryLock.Unlock()
+	}
+}
+
+// Ensures custom tags are parsed, stripped, and exposed in the Custom maps
+func TestRegisterTag(t *testing.T) {
+	defer registerTestTags(t,
+		TagDefinition{
+			Name:     "@SINCE",
+			HasValue: true,
+			Scope:    TagScopeResource | TagScopeAttribute,
+			Validate: func(value string) error {
+				if !strings.Contains(value, ".") {
+					return fmt.Errorf("Expected a version.")
+				}
+				return nil
+			},
+		},
+		TagDefinition{Name: "@TEAM", HasValue: true, Scope: TagScopeResource},
+		TagDefinition{Name: "@BETA", Scope: TagScopeAttribute},
+	)()
+
+	resource := &schema.Resource{
+		Description: "A foo. @TEAM networking @SINCE 2.3.0",
+		Schema: map[string]*schema.Schema{
+			"replicas": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Replica count. @BETA @SINCE 2.4.0 @EXAMPLE 3",
+			},
+		},
+	}
+
+	resourceMeta := parseMeta(typeResource, resource, resource.Schema)
+	expected := map[string]string{"TEAM": "networking", "SINCE": "2.3.0"}
+	if resourceMeta.Summary != "A foo." || !reflect.DeepEqual(resourceMeta.Custom, expected) {
+		t.Fatalf(
+			"parseMeta did not return the correct output. Expected [A foo.] "+
+				"%v, got [%s] %v.",
+			expected,
+			resourceMeta.Summary,
+			resourceMeta.Custom,
+		)
+	}
+
+	args := schemaArguments(resource.Schema, "")
+	attrs := schemaAttributes(resource.Schema, "")
+	expected = map[string]string{"BETA": "true", "SINCE": "2.4.0"}
+	if args[0].Description != "Replica count." || args[0].Example != "3" ||
+		!reflect.DeepEqual(args[0].Custom, expected) ||
+		!reflect.DeepEqual(attrs[0].Custom, expected) {
+		t.Fatalf(
+			"schemaArguments did not return the correct output. Expected "+
+				"[Replica count.] %v, got %+v %+v.",
+			expected,
+			args[0],
+			attrs[0],
+		)
+	}
+
+	if err := validateMetaTags("foo_bar", resource, resource.Schema); err != nil {
+		t.Fatalf("validateMetaTags returned an error: [%s]", err)
+	}
+	resource.Schema["replicas"].Description = "Replica count. @TEAM compute"
+	expectedErr := "Cannot parse the metadata tags of [foo_bar.replicas]. " +
+		"Error: [Tag [@TEAM] cannot be used on an attribute.]"
+	if err := validateMetaTags("foo_bar", resource, resource.Schema); err == nil || err.Error() != expectedErr {
+		t.Fatalf(
+			"validateMetaTags did not return the correct error. Expected "+
+				"[%s], got [%v].",
+			expectedErr,
+			err,
+		)
+	}
+	resource.Schema["replicas"].Description = "Replica count. @SINCE soon"
+	expectedErr = "Cannot parse the metadata tags of [foo_bar.replicas]. " +
+		"Error: [Invalid value [soon] of tag [@SINCE]. Expected a version.]"
+	if err := validateMetaTags("foo_bar", resource, resource.Schema); err == nil || err.Error() != expectedErr {
+		t.Fatalf(
+			"validateMetaTags did not return the correct error. Expected "+
+				"[%s], got [%v].",
+			expectedErr,
+			err,
+		)
+	}
+}
+
+// Ensures invalid tag definitions are rejected
+func TestRegisterTag_Errors(t *testing.T) {
+	defer registerTestTags(t)()
+
+	for _, def := range []TagDefinition{
+		{Name: "SINCE", Scope: TagScopeAttribute},
+		{Name: "@since", Scope: TagScopeAttribute},
+		{Name: MetaExample, Scope: TagScopeAttribute},
+		{Name: "@SINCE"},
+	} {
+		if err := RegisterTag(def); err == nil {
+			t.Fatalf("RegisterTag did not return an error for [%+v]", def)
+		}
+	}
+}
+
+// Ensures tags can be registered while descriptions are parsed, and a tag
+// registered concurrently is only registered once
+func TestRegisterTag_Concurrent(t *testing.T) {
+	defer registerTestTags(t)()
+
+	wg := sync.WaitGroup{}
+	registered := make(chan bool, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			err := RegisterTag(TagDefinition{Name: "@SINCE", HasValue: true, Scope: TagScopeAttribute})
+			registered <- err == nil
+		}()
+		go func() {
+			defer wg.Done()
+			parseDescription("Replica count. @SINCE 2.4.0 @EXAMPLE 3")
+		}()
+	}
+	wg.Wait()
+	close(registered)
+
+	successes := 0
+	for ok := range registered {
+		if ok {
+			successes++
+		}
+	}
+	if successes != 1 {
+		t.Fatalf("RegisterTag did not return the correct output. Expected [1] success, got [%d].", successes)
+	}
+	if _, ok := lookupMetaTag("@SINCE"); !ok {
+		t.Fatalf("RegisterTag did not register [@SINCE].")
+	}
+}
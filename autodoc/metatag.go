@@ -0,0 +1,331 @@
+package autodoc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the metadata tag syntax, be
+//   sure to update the documentation! This includes:
+//
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Tag syntax characters
+const (
+	// Starts a metadata tag
+	metaTagPrefix = '@'
+	// Escapes a metadata tag prefix or a quote, so it is read literally
+	metaEscape = '\\'
+	// Delimits a quoted tag value
+	metaQuote = '\''
+)
+
+// -----------------------------------------------------------------------------
+// Metadata Tag Data Structs
+// -----------------------------------------------------------------------------
+
+// A metadata tag found in a description
+type metaTagToken struct {
+	// Name of the tag, including the '@'
+	name string
+	// Value of the tag. Empty for tags that do not accept a value.
+	value string
+}
+
+// A description split into its text and its metadata tags
+type parsedDescription struct {
+	// The description with the known tags and their values removed
+	text string
+	// Known tags, in the order they appear
+	tags []metaTagToken
+	// Words that look like tags but are not known tags, in the order they
+	// appear. These are left in the text.
+	unknown []string
+	// Tags that accept a single value but appear more than once, in the
+	// order they are repeated. Only the first value of the tag is kept.
+	repeated []string
+}
+
+// -----------------------------------------------------------------------------
+// Metadata Tag Tokenizer
+// -----------------------------------------------------------------------------
+
+// parseDescription splits a schema description into its text and metadata
+// tags.
+//
+// A tag is an '@' at the start of the description or after whitespace,
+// followed by upper case letters, digits, and underscores, and ending at the
+// end of the word: "@IMMUTABLEish" and "user@example.com" are not tags. Only
+// tags in the tag registry are parsed; other tag-like words are reported as
+// unknown and left in the text. "\@" is a literal '@' that never starts a tag.
+//
+// The value of a tag that accepts one runs to the next known tag or the end
+// of the description, with surrounding whitespace removed. A value that starts
+// with a single quote runs to the next unescaped single quote instead, so it
+// can contain anything (ie: "@EXAMPLE '\@x @IMMUTABLE'"); "\'" is a literal
+// quote inside it. Text after the closing quote is description text.
+//
+// A tag that accepts a single value and is repeated keeps its first value;
+// the repetitions are removed from the text and listed as repeated. An error
+// is returned if a quoted value is not terminated. The description is still
+// parsed as far as possible.
+func parseDescription(descr string) (parsedDescription, error) {
+	parsed := parsedDescription{tags: []metaTagToken{}, unknown: []string{}, repeated: []string{}}
+	var parseErr error
+	text := []string{}
+	seen := map[string]bool{}
+
+	pos := 0
+	for pos < len(descr) {
+		segment, unknown, tag, next := scanDescription(descr, pos)
+		if trimmed := strings.TrimSpace(segment); trimmed != "" {
+			text = append(text, trimmed)
+		}
+		parsed.unknown = append(parsed.unknown, unknown...)
+		if tag == "" {
+			break
+		}
+
+		def, _ := lookupMetaTag(tag)
+		token := metaTagToken{name: tag}
+		pos = next + len(tag)
+		if def.hasValue {
+			var valueErr error
+			token.value, pos, valueErr = scanMetaValue(descr, pos, tag)
+			if valueErr != nil && parseErr == nil {
+				parseErr = valueErr
+			}
+		}
+
+		if seen[tag] && !def.repeatable {
+			parsed.repeated = append(parsed.repeated, tag)
+			continue
+		}
+		seen[tag] = true
+		parsed.tags = append(parsed.tags, token)
+	}
+
+	parsed.text = strings.Join(text, " ")
+	return parsed, parseErr
+}
+
+// scanDescription scans the description from pos to the next known tag.
+// Returns the text before the tag with escapes removed, the unknown tags in
+// it, and the name and position of the known tag. The name is empty if the
+// end of the description was reached.
+func scanDescription(descr string, pos int) (string, []string, string, int) {
+	b := strings.Builder{}
+	unknown := []string{}
+	for pos < len(descr) {
+		c := descr[pos]
+		if c == metaEscape && pos+1 < len(descr) && descr[pos+1] == metaTagPrefix {
+			// escaped references keep their escape until they are resolved
+			if isRefStart(descr, pos+1) {
+				b.WriteByte(metaEscape)
+			}
+			b.WriteByte(metaTagPrefix)
+			pos += 2
+			continue
+		}
+		if c == metaTagPrefix && (pos == 0 || isMetaSpace(descr[pos-1])) {
+			// references are resolved in the text; see replaceRefs
+			if isRefStart(descr, pos) {
+				b.WriteString(MetaRef)
+				pos += len(MetaRef)
+				continue
+			}
+			if tag := metaTagWord(descr, pos); tag != "" {
+				if _, ok := lookupMetaTag(tag); ok {
+					return b.String(), unknown, tag, pos
+				}
+				unknown = append(unknown, tag)
+				b.WriteString(tag)
+				pos += len(tag)
+				continue
+			}
+		}
+		b.WriteByte(c)
+		pos++
+	}
+	return b.String(), unknown, "", pos
+}
+
+// scanMetaValue scans the value of a tag starting at pos, right after the
+// tag. Returns the value and the position after it.
+func scanMetaValue(descr string, pos int, tag string) (string, int, error) {
+	start := pos
+	for start < len(descr) && isMetaSpace(descr[start]) {
+		start++
+	}
+
+	// unquoted values run to the next known tag
+	if start >= len(descr) || descr[start] != metaQuote {
+		value, _, _, next := scanDescription(descr, pos)
+		return strings.TrimSpace(value), next, nil
+	}
+
+	b := strings.Builder{}
+	for pos = start + 1; pos < len(descr); pos++ {
+		c := descr[pos]
+		if c == metaEscape && pos+1 < len(descr) && (descr[pos+1] == metaQuote || descr[pos+1] == metaTagPrefix) {
+			b.WriteByte(descr[pos+1])
+			pos++
+			continue
+		}
+		if c == metaQuote {
+			return b.String(), pos + 1, nil
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), pos, fmt.Errorf(
+		"The quoted value of tag [%s] is not terminated.",
+		tag,
+	)
+}
+
+// metaTagWord returns the tag-like word starting with the '@' at pos, or the
+// empty string if the word is not shaped like a tag
+func metaTagWord(descr string, pos int) string {
+	end := pos + 1
+	if end >= len(descr) || descr[end] < 'A' || descr[end] > 'Z' {
+		return ""
+	}
+	for end < len(descr) && isMetaTagChar(descr[end]) {
+		end++
+	}
+	// the tag must end at a word boundary
+	if end < len(descr) && isMetaWordChar(descr[end]) {
+		return ""
+	}
+	return descr[pos:end]
+}
+
+// isMetaTagChar returns whether or not the character can be part of a tag
+// name
+func isMetaTagChar(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_'
+}
+
+// isMetaWordChar returns whether or not the character continues a word
+func isMetaWordChar(c byte) bool {
+	return isMetaTagChar(c) || (c >= 'a' && c <= 'z')
+}
+
+// isMetaSpace returns whether or not the character is whitespace
+func isMetaSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// -----------------------------------------------------------------------------
+// Parsed Description Functions
+// -----------------------------------------------------------------------------
+
+// has returns whether or not the description has the tag
+func (p parsedDescription) has(tag string) bool {
+	for _, token := range p.tags {
+		if token.name == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// value returns the value of the tag, or the empty string if the description
+// does not have the tag
+func (p parsedDescription) value(tag string) string {
+	for _, token := range p.tags {
+		if token.name == tag {
+			return token.value
+		}
+	}
+	return ""
+}
+
+// values returns the values of every occurrence of a repeatable tag
+func (p parsedDescription) values(tag string) []string {
+	values := []string{}
+	for _, token := range p.tags {
+		if token.name == tag {
+			values = append(values, token.value)
+		}
+	}
+	return values
+}
+
+// parseScopedDescription parses a description like parseDescription, and
+// additionally checks that every custom tag may be used in the scope of the
+// description and that its value is valid
+func parseScopedDescription(descr string, scope TagScope) (parsedDescription, error) {
+	parsed, parseErr := parseDescription(descr)
+	if parseErr != nil {
+		return parsed, parseErr
+	}
+	for _, token := range parsed.tags {
+		def, _ := lookupMetaTag(token.name)
+		if !def.custom {
+			continue
+		}
+		if def.scope&scope == 0 {
+			where := "an attribute"
+			if scope == TagScopeResource {
+				where = "a resource"
+			}
+			return parsed, fmt.Errorf("Tag [%s] cannot be used on %s.", token.name, where)
+		}
+		if def.validate != nil {
+			if validateErr := def.validate(token.value); validateErr != nil {
+				return parsed, fmt.Errorf(
+					"Invalid value [%s] of tag [%s]. %s",
+					token.value,
+					token.name,
+					validateErr.Error(),
+				)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// metaTagScope returns the scope of the description of the attribute at the
+// path: the meta attribute describes the resource
+func metaTagScope(path string) TagScope {
+	if path == MetaAttribute {
+		return TagScopeResource
+	}
+	return TagScopeAttribute
+}
+
+// validateMetaTags parses the description of the resource and every
+// attribute of the schema map, and returns an error for the first
+// description that cannot be parsed or uses a custom tag incorrectly. object
+// is the name of the provider, resource, or data source; resource is nil for
+// the provider.
+func validateMetaTags(object string, resource *schema.Resource, schemaMap map[string]*schema.Schema) error {
+	if resource != nil {
+		if _, parseErr := parseScopedDescription(resource.Description, TagScopeResource); parseErr != nil {
+			return metaTagError(object, "", parseErr)
+		}
+	}
+	var firstErr error
+	walkSchema(schemaMap, "", func(path string, s *schema.Schema) {
+		if _, parseErr := parseScopedDescription(s.Description, metaTagScope(path)); parseErr != nil && firstErr == nil {
+			firstErr = metaTagError(object, path, parseErr)
+		}
+	})
+	return firstErr
+}
+
+// metaTagError returns a parse error of the metadata tags of an attribute,
+// or of the object itself if path is empty
+func metaTagError(object string, path string, parseErr error) error {
+	if path != "" {
+		object += "." + path
+	}
+	return fmt.Errorf(
+		"Cannot parse the metadata tags of [%s]. Error: [%s]",
+		object,
+		parseErr.Error(),
+	)
+}
@@ -0,0 +1,145 @@
+package autodoc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// parseDescription
+// -----------------------------------------------------------------------------
+
+// Ensures tags are recognized at word boundaries only, values can be quoted
+// and escaped, and repeatable tags keep every value
+func TestParseDescription(t *testing.T) {
+	cases := []struct {
+		descr   string
+		text    string
+		tags    []metaTagToken
+		unknown []string
+	}{
+		{
+			descr: "Name of the bar. @EXAMPLE \"bar\" @UNEXPORTED",
+			text:  "Name of the bar.",
+			tags: []metaTagToken{
+				{MetaExample, "\"bar\""},
+				{MetaUnexported, ""},
+			},
+		},
+		{
+			descr: "Owner. @EXAMPLE \"owner@example.com\"",
+			text:  "Owner.",
+			tags:  []metaTagToken{{MetaExample, "\"owner@example.com\""}},
+		},
+		{
+			descr:   "Mostly @IMMUTABLEish, see @Override and @FOO.",
+			text:    "Mostly @IMMUTABLEish, see @Override and @FOO.",
+			tags:    []metaTagToken{},
+			unknown: []string{"@FOO"},
+		},
+		{
+			descr: "Decorator. @EXAMPLE '\\@IMMUTABLE x @IMMUTABLE it\\'s' Quoted.",
+			text:  "Decorator. Quoted.",
+			tags:  []metaTagToken{{MetaExample, "@IMMUTABLE x @IMMUTABLE it's"}},
+		},
+		{
+			descr: "Escaped \\@IMMUTABLE tag.",
+			text:  "Escaped @IMMUTABLE tag.",
+			tags:  []metaTagToken{},
+		},
+		{
+			descr: "@NOLINT empty-description @IMMUTABLE Bar. @NOLINT unknown-tag @IMMUTABLE",
+			text:  "Bar.",
+			tags: []metaTagToken{
+				{MetaNoLint, "empty-description"},
+				{MetaImmutable, ""},
+				{MetaNoLint, "unknown-tag"},
+				{MetaImmutable, ""},
+			},
+		},
+	}
+	for _, c := range cases {
+		parsed, err := parseDescription(c.descr)
+		if err != nil {
+			t.Fatalf("parseDescription returned an error for [%s]: [%s]", c.descr, err)
+		}
+		if c.unknown == nil {
+			c.unknown = []string{}
+		}
+		if parsed.text != c.text ||
+			!reflect.DeepEqual(parsed.tags, c.tags) ||
+			!reflect.DeepEqual(parsed.unknown, c.unknown) {
+			t.Fatalf(
+				"parseDescription did not return the correct output for [%s]. "+
+					"Expected [%s] %+v %v, got [%s] %+v %v.",
+				c.descr,
+				c.text, c.tags, c.unknown,
+				parsed.text, parsed.tags, parsed.unknown,
+			)
+		}
+	}
+}
+
+// Ensures malformed tags are reported
+func TestParseDescription_Errors(t *testing.T) {
+	cases := map[string]string{
+		"@EXAMPLE 'never closed": "The quoted value of tag [@EXAMPLE] is not terminated.",
+	}
+	for descr, expected := range cases {
+		if _, err := parseDescription(descr); err == nil || err.Error() != expected {
+			t.Fatalf(
+				"parseDescription did not return the correct error for [%s]. "+
+					"Expected [%s], got [%v].",
+				descr,
+				expected,
+				err,
+			)
+		}
+	}
+}
+
+// Ensures a repeated single value tag keeps its first value and is reported
+// as repeated instead of failing the parse
+func TestParseDescription_Repeated(t *testing.T) {
+	parsed, err := parseDescription("Foo. @EXAMPLE 1 @IMMUTABLE @EXAMPLE 2 @IMMUTABLE")
+	if err != nil {
+		t.Fatalf("parseDescription returned an error: [%s]", err)
+	}
+	if parsed.text != "Foo." || parsed.value(MetaExample) != "1" ||
+		strings.Join(parsed.repeated, ",") != MetaExample {
+		t.Fatalf(
+			"parseDescription did not return the correct output. Expected "+
+				"[Foo.] [1] [%s], got [%s] [%s] %v.",
+			MetaExample,
+			parsed.text,
+			parsed.value(MetaExample),
+			parsed.repeated,
+		)
+	}
+}
+
+// Ensures parse errors fail the generation and name the attribute
+func TestDocumentWithOptions_InvalidTag(t *testing.T) {
+	provider := providerFoo()
+	rule := provider.ResourcesMap["foo_bar"].Schema["rule"].Elem.(*schema.Resource)
+	rule.Schema["priority"].Description = "Priority. @EXAMPLE '1"
+
+	errs := DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   NewMemoryFileSystem(),
+	})
+	expected := "Cannot parse the metadata tags of [foo_bar.rule.priority]. " +
+		"Error: [The quoted value of tag [@EXAMPLE] is not terminated.]"
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Expected "+
+				"[%s], got %v.",
+			expected,
+			errs,
+		)
+	}
+}
@@ -0,0 +1,169 @@
+package autodoc
+
+import (
+	"regexp"
+	"strings"
+)
+
+// NOTE(ALL): If you make modifications to the keys of mkdocs.yml that are
+//   generated, be sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Top level keys of mkdocs.yml that autodoc generates. All other keys of an
+// existing mkdocs.yml (ie: theme, plugins, markdown_extensions) belong to the
+// user and are kept as they are.
+var mkdocsGeneratedKeys = map[string]bool{
+	"site_name": true,
+	"docs_dir":  true,
+	"nav":       true,
+}
+
+// Matches a line that starts a top level YAML mapping key (ie: "nav:" or
+// "site_name: foo"), capturing the key
+var yamlTopLevelKeyRegexp = regexp.MustCompile(`^([^\s#\-][^:]*):(\s|$)`)
+
+// -----------------------------------------------------------------------------
+// mkdocs.yml Data Structs
+// -----------------------------------------------------------------------------
+
+// A top level key of a YAML document and the lines that belong to it
+type yamlKeyBlock struct {
+	// The key. Empty for the lines before the first key.
+	key string
+	// Blank and comment lines before the key, including their newlines
+	leading []string
+	// The key line and the indented lines of its value, including their
+	// newlines
+	body []string
+}
+
+// -----------------------------------------------------------------------------
+// mkdocs.yml Utility Functions
+// -----------------------------------------------------------------------------
+
+// mergeMkdocsYml merges the generated keys of the generated mkdocs.yml into
+// the existing one. Each generated key replaces the value of the same key in
+// the existing file, in place. Generated keys the existing file does not
+// have are inserted after the generated key before them, or before the first
+// key of the file. All other keys, their order, and their comments are kept
+// as they are.
+//
+// The merge works on the lines of the files rather than a parsed document,
+// so the user's formatting, comments, and YAML tags (ie: "!!python/name:")
+// survive. Only block style top level keys are recognized.
+func mergeMkdocsYml(existing []byte, generated []byte) []byte {
+	generatedBlocks := map[string]yamlKeyBlock{}
+	generatedOrder := []string{}
+	for _, block := range splitYAMLKeys(string(generated)) {
+		if !mkdocsGeneratedKeys[block.key] {
+			continue
+		}
+		if _, ok := generatedBlocks[block.key]; !ok {
+			generatedBlocks[block.key] = block
+			generatedOrder = append(generatedOrder, block.key)
+		}
+	}
+
+	merged := splitYAMLKeys(string(existing))
+	replaced := map[string]bool{}
+	for idx, block := range merged {
+		if generatedBlock, ok := generatedBlocks[block.key]; ok && !replaced[block.key] {
+			merged[idx].body = generatedBlock.body
+			replaced[block.key] = true
+		}
+	}
+
+	// generated keys the existing file does not have follow the generated
+	// key before them, or go before the first key of the file. The first
+	// block holds the lines before the first key.
+	for idx, key := range generatedOrder {
+		if replaced[key] {
+			continue
+		}
+		at := 1
+		if idx > 0 {
+			for blockIdx, block := range merged {
+				if block.key == generatedOrder[idx-1] {
+					at = blockIdx + 1
+				}
+			}
+		}
+		block := yamlKeyBlock{key: key, body: generatedBlocks[key].body}
+		merged = append(merged[:at], append([]yamlKeyBlock{block}, merged[at:]...)...)
+		replaced[key] = true
+	}
+
+	b := strings.Builder{}
+	for _, block := range merged {
+		writeYAMLLines(&b, block.leading)
+		writeYAMLLines(&b, block.body)
+	}
+	return []byte(b.String())
+}
+
+// writeYAMLLines writes the lines, ending the last line of a file without a
+// trailing newline so another line can follow it
+func writeYAMLLines(b *strings.Builder, lines []string) {
+	for _, line := range lines {
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+	}
+}
+
+// splitYAMLKeys splits a YAML document into its top level keys. The first
+// block always holds the lines before the first key, and has no key. Blank
+// and comment lines between two keys belong to the second one, so they stay
+// with it when the value of the first one is replaced.
+func splitYAMLKeys(content string) []yamlKeyBlock {
+	blocks := []yamlKeyBlock{{}}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		match := yamlTopLevelKeyRegexp.FindStringSubmatch(line)
+		if match == nil {
+			last := &blocks[len(blocks)-1]
+			if last.key == "" {
+				last.leading = append(last.leading, line)
+			} else {
+				last.body = append(last.body, line)
+			}
+			continue
+		}
+
+		// the trailing blank and comment lines of the previous key lead
+		// this one
+		last := &blocks[len(blocks)-1]
+		lines := last.body
+		if last.key == "" {
+			lines = last.leading
+		}
+		cut := len(lines)
+		for cut > 0 && isYAMLTrivia(lines[cut-1]) {
+			cut--
+		}
+		leading := append([]string{}, lines[cut:]...)
+		if last.key == "" {
+			last.leading = lines[:cut]
+		} else {
+			last.body = lines[:cut]
+		}
+
+		blocks = append(blocks, yamlKeyBlock{
+			key:     strings.Trim(strings.TrimSpace(match[1]), `"'`),
+			leading: leading,
+			body:    []string{line},
+		})
+	}
+	return blocks
+}
+
+// isYAMLTrivia returns whether or not the line is blank or a comment that
+// starts in the first column
+func isYAMLTrivia(line string) bool {
+	return strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#")
+}
@@ -0,0 +1,117 @@
+package autodoc
+
+import (
+	"bytes"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// mkdocs.yml
+// -----------------------------------------------------------------------------
+
+// Ensures the generated keys replace the existing ones in place, and all
+// other keys, comments, and tags are kept
+func TestMergeMkdocsYml(t *testing.T) {
+	existing := "# Site configuration\n" +
+		"site_name: Old Name\n" +
+		"theme:\n" +
+		"  name: material\n" +
+		"\n" +
+		"# Navigation is generated\n" +
+		"nav:\n" +
+		"- Home: 'index.md'\n" +
+		"- Old: 'old.md'\n" +
+		"\n" +
+		"markdown_extensions:\n" +
+		"  - pymdownx.emoji:\n" +
+		"      emoji_index: !!python/name:material.extensions.emoji.twemoji\n" +
+		"extra_css: [css/extra.css]"
+	generated := "site_name: terraform-provider-foo\n" +
+		"docs_dir: docs\n" +
+		"\n" +
+		"nav:\n" +
+		"  - Home: 'index.md'\n" +
+		"  - Resources:\n" +
+		"    - foo_bar: 'resources/foo_bar.md'\n" +
+		"\n" +
+		"theme:\n" +
+		"  name: readthedocs\n"
+	expected := "# Site configuration\n" +
+		"site_name: terraform-provider-foo\n" +
+		"docs_dir: docs\n" +
+		"theme:\n" +
+		"  name: material\n" +
+		"\n" +
+		"# Navigation is generated\n" +
+		"nav:\n" +
+		"  - Home: 'index.md'\n" +
+		"  - Resources:\n" +
+		"    - foo_bar: 'resources/foo_bar.md'\n" +
+		"\n" +
+		"markdown_extensions:\n" +
+		"  - pymdownx.emoji:\n" +
+		"      emoji_index: !!python/name:material.extensions.emoji.twemoji\n" +
+		"extra_css: [css/extra.css]\n"
+
+	actual := string(mergeMkdocsYml([]byte(existing), []byte(generated)))
+	if actual != expected {
+		t.Fatalf(
+			"mergeMkdocsYml did not return the correct output. Expected:\n%s\ngot:\n%s",
+			expected,
+			actual,
+		)
+	}
+}
+
+// Ensures an existing mkdocs.yml keeps its user-owned keys when the
+// documentation is regenerated, and is created from the template otherwise
+func TestDocumentWithOptions_MkdocsMerge(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	opts := Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+		Out:          &bytes.Buffer{},
+	}
+	if errs := DocumentWithOptions(providerFoo(), opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	created, _ := fs.ReadFile("/out/mkdocs.yml")
+	if !bytes.HasPrefix(created, []byte("site_name: terraform-provider-foo\ndocs_dir: docs\n")) {
+		t.Fatalf(
+			"DocumentWithOptions did not render mkdocs.yml from the template. Got:\n%s",
+			string(created),
+		)
+	}
+
+	fs.WriteFile("/out/mkdocs.yml", []byte("site_name: Foo\nplugins:\n  - search\nnav:\n  - Old: 'old.md'\n"), 0644)
+	provider := providerFoo()
+	provider.ResourcesMap["foo_new"] = resourceFoo()
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	merged, _ := fs.ReadFile("/out/mkdocs.yml")
+	expected := "site_name: terraform-provider-foo\n" +
+		"docs_dir: docs\n" +
+		"plugins:\n  - search\n" +
+		"nav:\n  - Home: 'index.md'\n  - Resources:\n" +
+		"    - foo_bar: 'resources/foo_bar.md'\n" +
+		"    - foo_new: 'resources/foo_new.md'\n" +
+		"  - Data Sources:\n    - foo_baz: 'datasources/foo_baz.md'\n" +
+		"  - Godoc: 'godoc.md'\n"
+	if string(merged) != expected {
+		t.Fatalf(
+			"DocumentWithOptions did not merge mkdocs.yml. Expected:\n%s\ngot:\n%s",
+			expected,
+			string(merged),
+		)
+	}
+
+	// the merged file is up to date
+	opts.Check = true
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("Check returned errors for the merged mkdocs.yml: %v", errs)
+	}
+}
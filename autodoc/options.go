@@ -0,0 +1,152 @@
+package autodoc
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// NOTE(ALL): Options mirrors the command line arguments. If you make
+//   modifications to the options, be sure to update the argument parsing in
+//   args.go and the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Options configures a documentation run started with DocumentWithOptions.
+// Any field left as its zero value is set to the same default used by the
+// command line arguments.
+type Options struct {
+	// The name of the provider being documented. Defaults to
+	// "Terraform Provider".
+	ProviderName string
+	// The root output directory- mkdocs.yml will be placed here. Defaults to
+	// the current working directory.
+	RootDir string
+	// The documentation directory. All autogenerated markdown files will be
+	// placed in this directory. The mkdocs.yml docs_dir will be set to this
+	// value. Defaults to 'docs' under RootDir.
+	DocsDir string
+	// The location to read & load template files. Defaults to 'templates'
+	// under RootDir.
+	TemplatesDir string
+	// The file extension for template files. Defaults to '.template'.
+	TemplateExt string
+	// The output profile, one of the ProfileXxx constants. Defaults to
+	// ProfileMkdocs.
+	Profile string
+	// The filesystem generated files are written to. Defaults to the
+	// operating system's filesystem. Templates are always read from the
+	// operating system's filesystem.
+	FileSystem FileSystem
+	// Whether or not to check that the documentation on the filesystem is up
+	// to date instead of writing it. Out of date files are reported to Out
+	// and an error is returned.
+	Check bool
+	// Whether or not to list the files that would be created, updated, and
+	// removed instead of writing them. The list is written to Out.
+	DryRun bool
+	// Whether or not to report the status (created, updated, unchanged, or
+	// removed) of each generated file to Out, in addition to the counts
+	Verbose bool
+	// Whether or not to write the built-in templates to TemplatesDir instead
+	// of generating documentation. Existing templates are not over-written.
+	DumpTemplates bool
+	// Whether or not to lint the schema documentation instead of writing
+	// it. The lint report is written to Out and an error is returned if any
+	// error severity findings are reported.
+	Lint bool
+	// Format of the lint report, one of the LintFormatXxx constants.
+	// Defaults to LintFormatText.
+	LintFormat string
+	// Severity of lint rules by rule ID (one of the LintXxx constants). Rules
+	// that are not listed use their default severity.
+	LintRules map[string]LintSeverity
+	// Path to write the JSON schema document of the provider to instead of
+	// generating documentation. The document has the shape of the output of
+	// `terraform providers schema -json` with autodoc metadata added. "-"
+	// writes it to Out.
+	SchemaJSON string
+	// Path to a JSON schema snapshot (written with SchemaJSON) to generate a
+	// schema changelog from, instead of generating documentation
+	ChangelogFrom string
+	// Path to the JSON schema snapshot to generate the changelog to.
+	// Defaults to the schema of the provider being documented.
+	ChangelogTo string
+	// Format of the changelog, one of the ChangelogFormatXxx constants.
+	// Defaults to ChangelogFormatMarkdown.
+	ChangelogFormat string
+	// Path to write the changelog to. Defaults to "-", which writes it to
+	// Out.
+	ChangelogOut string
+	// Path to the schema baseline (a JSON schema snapshot) to check the
+	// compatibility of the provider against, instead of generating
+	// documentation. Changes are reported to Out and an error is returned if
+	// any breaking change is not in the allow file.
+	Compat string
+	// Whether or not to write the schema baseline to the Compat path instead
+	// of checking it
+	CompatUpdate bool
+	// Path to the allow file listing intended breaking changes, each with a
+	// justification. Optional.
+	CompatAllow string
+	// Directories of the Go packages to generate the API reference for. A
+	// directory ending in "/..." also selects every package under it (ie:
+	// "./..."). Relative directories are relative to the current working
+	// directory.
+	GoPackages []string
+	// Functions available to templates, in addition to the built-in template
+	// functions. A function with the name of a built-in function over-rides
+	// it.
+	Funcs template.FuncMap
+	// Writer for reports, such as the diffs printed in check mode. Defaults
+	// to stdout.
+	Out io.Writer
+}
+
+// withDefaults returns a copy of the options with the default value set for
+// each unset field. An error is returned if a default value could not be
+// determined.
+func (o Options) withDefaults() (Options, error) {
+	if o.ProviderName == "" {
+		o.ProviderName = defaultProviderName
+	}
+	if o.RootDir == "" {
+		// Get the current working directory- used in default values
+		cwd, cwdErr := os.Getwd()
+		if cwdErr != nil {
+			return o, cwdErr
+		}
+		o.RootDir = cwd
+	}
+	if o.DocsDir == "" {
+		o.DocsDir = filepath.Join(o.RootDir, defaultDocsDir)
+	}
+	if o.TemplatesDir == "" {
+		o.TemplatesDir = filepath.Join(o.RootDir, defaultTemplatesDir)
+	}
+	if o.TemplateExt == "" {
+		o.TemplateExt = defaultTemplateFileExt
+	}
+	if o.Profile == "" {
+		o.Profile = ProfileMkdocs
+	}
+	if o.FileSystem == nil {
+		o.FileSystem = OSFileSystem{}
+	}
+	if o.LintFormat == "" {
+		o.LintFormat = LintFormatText
+	}
+	if o.ChangelogFormat == "" {
+		o.ChangelogFormat = ChangelogFormatMarkdown
+	}
+	if o.ChangelogOut == "" {
+		o.ChangelogOut = stdoutPath
+	}
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	return o, nil
+}
@@ -0,0 +1,427 @@
+package autodoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// File mode of generated documentation files
+	outputFileMode = 0775
+	// File mode of the directories created for generated files
+	outputDirMode = 0775
+	// Suffix of the temporary files generated files are written to before
+	// they are renamed
+	tmpFileSuffix = ".tmp"
+	// Name of the manifest of generated files, under the root directory. It
+	// lists the files written by the previous run, so the files that are no
+	// longer generated can be removed.
+	manifestFile = ".autodoc-manifest"
+	// First line of the manifest
+	manifestHeader = "# Generated by autodoc. Do not edit.\n"
+)
+
+// -----------------------------------------------------------------------------
+// Output Data Structs
+// -----------------------------------------------------------------------------
+
+// The difference between the rendered files and the files on the output
+// filesystem. Each list is sorted by path.
+type fileChanges struct {
+	// Rendered files that do not exist
+	created []renderedFile
+	// Rendered files that differ from the existing files
+	updated []fileUpdate
+	// Paths of the rendered files that are identical to the existing files
+	unchanged []string
+	// Paths of the generated files that would be removed
+	removed []string
+	// Paths of the pages in the generated page directories that were not
+	// rendered and are not listed in the manifest. They are kept.
+	unlisted []string
+}
+
+// A rendered file that differs from the existing file
+type fileUpdate struct {
+	renderedFile
+	// Contents of the existing file
+	existing []byte
+}
+
+// -----------------------------------------------------------------------------
+// Output Utility Functions
+// -----------------------------------------------------------------------------
+
+// writeFiles writes each rendered file to the output filesystem, creating
+// its directory if necessary. Files whose content hash matches the existing
+// file are not written, so their modification times do not change. Each file
+// is written to a temporary file next to it and renamed over it, so a failed
+// write does not leave a partially written file. Once every file is written,
+// the files generated by the previous run that were not generated by this one
+// are removed and the manifest is updated. The number of files created,
+// updated, unchanged, and removed is reported to the options' output,
+// preceded by the status of each file in verbose mode. Returns a list of
+// errors. If this list is empty, all files were written.
+func writeFiles(opts Options, site siteGenerator, files []renderedFile) []error {
+	changes, errors := diffFiles(opts, site, files)
+	if len(errors) > 0 {
+		return errors
+	}
+
+	writes := append([]renderedFile{}, changes.created...)
+	for _, file := range changes.updated {
+		writes = append(writes, file.renderedFile)
+	}
+	for _, file := range writes {
+		if writeErr := writeFileAtomic(opts.FileSystem, file.path, file.content); writeErr != nil {
+			errors = append(errors, fmt.Errorf(
+				"Cannot generate [%s]. Failed to write file. Error: [%s]",
+				file.path,
+				writeErr.Error(),
+			))
+		}
+	}
+	// The previous output is only pruned once the new output is complete
+	if len(errors) > 0 {
+		return errors
+	}
+
+	for _, path := range changes.removed {
+		removeErr := opts.FileSystem.Remove(path)
+		if removeErr != nil && !os.IsNotExist(removeErr) {
+			errors = append(errors, fmt.Errorf(
+				"Cannot remove [%s]. Error: [%s]",
+				path,
+				removeErr.Error(),
+			))
+		}
+	}
+	if manifestErr := writeManifest(opts, files); manifestErr != nil {
+		errors = append(errors, manifestErr)
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+
+	if opts.Verbose {
+		for _, file := range changes.created {
+			fmt.Fprintf(opts.Out, "Created: %s\n", displayPath(opts, file.path))
+		}
+		for _, file := range changes.updated {
+			fmt.Fprintf(opts.Out, "Updated: %s\n", displayPath(opts, file.path))
+		}
+		for _, path := range changes.unchanged {
+			fmt.Fprintf(opts.Out, "Unchanged: %s\n", displayPath(opts, path))
+		}
+		for _, path := range changes.removed {
+			fmt.Fprintf(opts.Out, "Removed: %s\n", displayPath(opts, path))
+		}
+	}
+	fmt.Fprintf(
+		opts.Out,
+		"Documentation generated. [%d] created, [%d] updated, [%d] "+
+			"unchanged, [%d] removed.\n",
+		len(changes.created),
+		len(changes.updated),
+		len(changes.unchanged),
+		len(changes.removed),
+	)
+	return errors
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of the
+// named file and renames it to the named file. The directory is created if
+// it does not exist.
+func writeFileAtomic(fs FileSystem, name string, data []byte) error {
+	if mkdirErr := fs.MkdirAll(filepath.Dir(name), outputDirMode); mkdirErr != nil {
+		return mkdirErr
+	}
+	tmp := filepath.Join(filepath.Dir(name), "."+filepath.Base(name)+tmpFileSuffix)
+	if writeErr := fs.WriteFile(tmp, data, outputFileMode); writeErr != nil {
+		fs.Remove(tmp)
+		return writeErr
+	}
+	if renameErr := fs.Rename(tmp, name); renameErr != nil {
+		fs.Remove(tmp)
+		return renameErr
+	}
+	return nil
+}
+
+// dryRunFiles lists the files that writing the rendered files would create,
+// update, and remove, without writing anything. Returns a list of errors. If
+// this list is empty, the files were listed.
+func dryRunFiles(opts Options, site siteGenerator, files []renderedFile) []error {
+	changes, errors := diffFiles(opts, site, files)
+	for _, file := range changes.created {
+		fmt.Fprintf(opts.Out, "Would create: %s\n", displayPath(opts, file.path))
+	}
+	for _, file := range changes.updated {
+		fmt.Fprintf(opts.Out, "Would update: %s\n", displayPath(opts, file.path))
+	}
+	for _, path := range changes.removed {
+		fmt.Fprintf(opts.Out, "Would remove: %s\n", displayPath(opts, path))
+	}
+	warnUnlisted(opts, changes)
+	return errors
+}
+
+// checkFiles compares each rendered file to the existing file on the output
+// filesystem without writing anything. A unified diff is printed to the
+// options' output for every file that is out of date, followed by the list
+// of files that would be created and the generated files that would be
+// removed. Pages that are not listed in the manifest are reported as
+// warnings and do not make the documentation out of date. Returns a list of
+// errors. If this list is empty, the documentation on disk is up to date.
+func checkFiles(opts Options, site siteGenerator, files []renderedFile) []error {
+	changes, errors := diffFiles(opts, site, files)
+	for _, file := range changes.updated {
+		name := displayPath(opts, file.path)
+		fmt.Fprint(opts.Out, unifiedDiff("a/"+name, "b/"+name, file.existing, file.content))
+	}
+	for _, file := range changes.created {
+		fmt.Fprintf(opts.Out, "Would create: %s\n", displayPath(opts, file.path))
+	}
+	for _, path := range changes.removed {
+		fmt.Fprintf(opts.Out, "Would remove: %s\n", displayPath(opts, path))
+	}
+	warnUnlisted(opts, changes)
+
+	outdated := len(changes.created) + len(changes.updated) + len(changes.removed)
+	if outdated > 0 {
+		errors = append(errors, fmt.Errorf(
+			"Documentation is out of date. [%d] file(s) differ from the "+
+				"generated output. Run autodoc to update them.",
+			outdated,
+		))
+	}
+	return errors
+}
+
+// diffFiles compares the content hash of each rendered file to the hash of
+// the existing file on the output filesystem, and finds the generated files
+// that would be removed or are not listed in the manifest. Returns a list of
+// errors for the files and directories that could not be read.
+func diffFiles(opts Options, site siteGenerator, files []renderedFile) (fileChanges, []error) {
+	changes := fileChanges{}
+	errors := []error{}
+	for _, file := range files {
+		existing, readErr := opts.FileSystem.ReadFile(file.path)
+		if os.IsNotExist(readErr) {
+			changes.created = append(changes.created, file)
+			continue
+		}
+		if readErr != nil {
+			errors = append(errors, fmt.Errorf(
+				"Cannot check [%s]. Failed to read file. Error: [%s]",
+				file.path,
+				readErr.Error(),
+			))
+			continue
+		}
+		if contentHash(existing) == contentHash(file.content) {
+			changes.unchanged = append(changes.unchanged, file.path)
+			continue
+		}
+		changes.updated = append(changes.updated, fileUpdate{
+			renderedFile: file,
+			existing:     existing,
+		})
+	}
+	sort.Slice(changes.created, func(i, j int) bool {
+		return changes.created[i].path < changes.created[j].path
+	})
+	sort.Strings(changes.unchanged)
+	sort.Slice(changes.updated, func(i, j int) bool {
+		return changes.updated[i].path < changes.updated[j].path
+	})
+
+	previous, manifestErr := readManifest(opts)
+	if manifestErr != nil {
+		return changes, append(errors, manifestErr)
+	}
+	changes.removed = staleFiles(opts, files, previous)
+	unlisted, unlistedErrs := unlistedPages(opts, site, files, previous)
+	changes.unlisted = unlisted
+	return changes, append(errors, unlistedErrs...)
+}
+
+// warnUnlisted prints a warning for each page that is not listed in the
+// manifest. These pages are never removed, since autodoc cannot tell a page
+// it generated before the manifest existed from a hand-written one.
+func warnUnlisted(opts Options, changes fileChanges) {
+	for _, path := range changes.unlisted {
+		fmt.Fprintf(
+			opts.Out,
+			"Warning: %s was not generated and is not listed in the manifest. "+
+				"It is kept.\n",
+			displayPath(opts, path),
+		)
+	}
+}
+
+// staleFiles returns the sorted paths of the files listed in the manifest
+// of the previous run that exist on the output filesystem but were not
+// rendered. Files that are not in the manifest are never returned.
+func staleFiles(opts Options, files []renderedFile, previous []string) []string {
+	rendered := renderedPaths(files)
+	stale := map[string]bool{}
+	for _, path := range previous {
+		if rendered[path] {
+			continue
+		}
+		// the file may have been removed by hand since the previous run
+		if _, readErr := opts.FileSystem.ReadFile(path); readErr == nil {
+			stale[path] = true
+		}
+	}
+
+	paths := []string{}
+	for path := range stale {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// unlistedPages returns the sorted paths of the pages in the generated page
+// directories that were not rendered and are not listed in the manifest of
+// the previous run, ie: pages generated before the manifest existed or
+// written by hand. Returns a list of errors for the directories that could
+// not be read.
+func unlistedPages(
+	opts Options,
+	site siteGenerator,
+	files []renderedFile,
+	previous []string,
+) ([]string, []error) {
+	errors := []error{}
+	rendered := renderedPaths(files)
+	listed := map[string]bool{}
+	for _, path := range previous {
+		listed[path] = true
+	}
+
+	paths := []string{}
+	for _, dir := range generatedPageDirs(opts, site) {
+		names, readErr := opts.FileSystem.ReadDir(dir)
+		if os.IsNotExist(readErr) {
+			continue
+		}
+		if readErr != nil {
+			errors = append(errors, fmt.Errorf(
+				"Cannot check [%s]. Failed to read directory. Error: [%s]",
+				dir,
+				readErr.Error(),
+			))
+			continue
+		}
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if strings.HasSuffix(name, ".md") && !rendered[path] && !listed[path] {
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, errors
+}
+
+// renderedPaths returns the set of the cleaned paths of the rendered files
+func renderedPaths(files []renderedFile) map[string]bool {
+	paths := map[string]bool{}
+	for _, file := range files {
+		paths[filepath.Clean(file.path)] = true
+	}
+	return paths
+}
+
+// readManifest returns the cleaned paths of the files listed in the manifest
+// under the root directory. An empty list is returned if there is no
+// manifest, ie: on the first run.
+func readManifest(opts Options) ([]string, error) {
+	manifestPath := filepath.Join(opts.RootDir, manifestFile)
+	content, readErr := opts.FileSystem.ReadFile(manifestPath)
+	if os.IsNotExist(readErr) {
+		return []string{}, nil
+	}
+	if readErr != nil {
+		return nil, fmt.Errorf(
+			"Cannot read the manifest [%s]. Error: [%s]",
+			manifestPath,
+			readErr.Error(),
+		)
+	}
+	paths := []string{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		path := filepath.FromSlash(line)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(opts.RootDir, path)
+		}
+		paths = append(paths, filepath.Clean(path))
+	}
+	return paths, nil
+}
+
+// writeManifest writes the manifest of the rendered files under the root
+// directory. Paths under the root directory are listed relative to it, so
+// the manifest can be committed with the documentation. The manifest is not
+// written if it has not changed.
+func writeManifest(opts Options, files []renderedFile) error {
+	paths := []string{}
+	for _, file := range files {
+		paths = append(paths, displayPath(opts, filepath.Clean(file.path)))
+	}
+	sort.Strings(paths)
+	content := manifestHeader + strings.Join(paths, "\n") + "\n"
+
+	manifestPath := filepath.Join(opts.RootDir, manifestFile)
+	existing, readErr := opts.FileSystem.ReadFile(manifestPath)
+	if readErr == nil && contentHash(existing) == contentHash([]byte(content)) {
+		return nil
+	}
+	if writeErr := writeFileAtomic(opts.FileSystem, manifestPath, []byte(content)); writeErr != nil {
+		return fmt.Errorf(
+			"Cannot write the manifest [%s]. Error: [%s]",
+			manifestPath,
+			writeErr.Error(),
+		)
+	}
+	return nil
+}
+
+// contentHash returns the hex encoded SHA-256 hash of the file contents
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// generatedPageDirs returns the directories that contain one generated page
+// per resource or data source
+func generatedPageDirs(opts Options, site siteGenerator) []string {
+	dirs := []string{}
+	for _, dir := range site.pageDirs() {
+		dirs = append(dirs, filepath.Join(opts.DocsDir, filepath.FromSlash(dir)))
+	}
+	return dirs
+}
+
+// displayPath returns the path relative to the root directory for display
+// purposes. The path is returned unchanged if it is not under the root
+// directory.
+func displayPath(opts Options, path string) string {
+	rel, relErr := filepath.Rel(opts.RootDir, path)
+	if relErr != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
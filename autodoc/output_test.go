@@ -0,0 +1,250 @@
+package autodoc
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// Test Helpers
+// -----------------------------------------------------------------------------
+
+// failingRenameFileSystem is a MemoryFileSystem on which every rename fails
+type failingRenameFileSystem struct {
+	*MemoryFileSystem
+}
+
+// Rename always returns an error
+func (failingRenameFileSystem) Rename(oldName string, newName string) error {
+	return errors.New("disk full")
+}
+
+// recordingFileSystem is a MemoryFileSystem that records the names of the
+// files renamed into place, ie: the generated files that were written
+type recordingFileSystem struct {
+	*MemoryFileSystem
+	written []string
+}
+
+// Rename records the new name and renames the file
+func (r *recordingFileSystem) Rename(oldName string, newName string) error {
+	r.written = append(r.written, newName)
+	return r.MemoryFileSystem.Rename(oldName, newName)
+}
+
+// -----------------------------------------------------------------------------
+// Output
+// -----------------------------------------------------------------------------
+
+// Ensures the output directories are created on the operating system's
+// filesystem, and no temporary files are left behind
+func TestDocumentWithOptions_CreatesDirs(t *testing.T) {
+	tmpDir, tmpErr := ioutil.TempDir("", "autodoc")
+	if tmpErr != nil {
+		t.Fatalf("Could not create the output directory: [%s]", tmpErr)
+	}
+	defer os.RemoveAll(tmpDir)
+	root := filepath.Join(tmpDir, "fresh")
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:      root,
+		DocsDir:      filepath.Join(root, "docs"),
+		TemplatesDir: "/does/not/exist",
+		Out:          &bytes.Buffer{},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	for _, path := range []string{"mkdocs.yml", "docs/resources/foo_bar.md", "docs/datasources/foo_baz.md"} {
+		if _, statErr := os.Stat(filepath.Join(root, filepath.FromSlash(path))); statErr != nil {
+			t.Fatalf("DocumentWithOptions did not write [%s]. Error: [%s]", path, statErr)
+		}
+	}
+	names, _ := ioutil.ReadDir(filepath.Join(root, "docs", "resources"))
+	if len(names) != 1 {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"only foo_bar.md in the resources directory, got [%d] files.",
+			len(names),
+		)
+	}
+}
+
+// Ensures a failed write leaves the existing file as it was, and the
+// temporary file is removed
+func TestWriteFileAtomic(t *testing.T) {
+	fs := failingRenameFileSystem{NewMemoryFileSystem()}
+	fs.WriteFile("/out/docs/index.md", []byte("# old\n"), 0644)
+
+	if writeErr := writeFileAtomic(fs, "/out/docs/index.md", []byte("# new\n")); writeErr == nil {
+		t.Fatalf("writeFileAtomic did not return an error for a failed rename.")
+	}
+	content, _ := fs.ReadFile("/out/docs/index.md")
+	files := fs.Files()
+	if string(content) != "# old\n" || len(files) != 1 {
+		t.Fatalf(
+			"writeFileAtomic did not return the correct output. Expected the "+
+				"existing file only, got [%s] in %v.",
+			string(content),
+			files,
+		)
+	}
+}
+
+// Ensures the files generated by a previous run that are no longer generated
+// are removed, and files that are not in the manifest are kept
+func TestDocumentWithOptions_Prune(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	// a page written before the first run is not in any manifest
+	fs.WriteFile("/out/docs/resources/notes.md", []byte("# notes\n"), 0644)
+	opts := Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+	}
+	provider := providerFoo()
+	provider.ResourcesMap["foo_old"] = resourceFoo()
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	manifest, _ := fs.ReadFile("/out/.autodoc-manifest")
+	if !strings.Contains(string(manifest), "\ndocs/resources/foo_old.md\n") {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct manifest. Expected "+
+				"[docs/resources/foo_old.md] in:\n%s",
+			string(manifest),
+		)
+	}
+
+	// switching profiles leaves the mkdocs files in the manifest
+	fs.WriteFile("/out/docs/guide.md", []byte("# hand written\n"), 0644)
+	opts.Profile = ProfileBare
+	if errs := DocumentWithOptions(providerFoo(), opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	expectSiteFiles(t, fs, []string{
+		"/out/.autodoc-manifest",
+		"/out/docs/README.md",
+		"/out/docs/datasources/foo_baz.md",
+		"/out/docs/guide.md",
+		"/out/docs/resources/foo_bar.md",
+		"/out/docs/resources/notes.md",
+	}, map[string][]string{
+		"/out/.autodoc-manifest": {
+			"docs/README.md\ndocs/datasources/foo_baz.md\ndocs/resources/foo_bar.md\n",
+		},
+	})
+}
+
+// Ensures dry run mode lists the changes without writing anything
+func TestDocumentWithOptions_DryRun(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	templatesDir := writeTemplates(t, testTemplates())
+	defer os.RemoveAll(templatesDir)
+	opts := Options{
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: templatesDir,
+		FileSystem:   fs,
+	}
+	provider := providerFoo()
+	provider.ResourcesMap["foo_old"] = resourceFoo()
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	out := bytes.Buffer{}
+	opts.DryRun = true
+	opts.Out = &out
+	fs.WriteFile("/out/docs/resources/notes.md", []byte("# notes\n"), 0644)
+	before := fs.Files()
+	provider = providerFoo()
+	provider.ResourcesMap["foo_new"] = resourceFoo()
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	expected := "Would create: docs/resources/foo_new.md\n" +
+		"Would update: mkdocs.yml\n" +
+		"Would remove: docs/resources/foo_old.md\n" +
+		"Warning: docs/resources/notes.md was not generated and is not " +
+		"listed in the manifest. It is kept.\n"
+	if out.String() != expected {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"[%s], got [%s].",
+			expected,
+			out.String(),
+		)
+	}
+	if after := fs.Files(); strings.Join(before, ",") != strings.Join(after, ",") {
+		t.Fatalf("DocumentWithOptions wrote files in dry run mode: %v", after)
+	}
+}
+
+// Ensures files whose content has not changed are not written, and the
+// status of each file is reported
+func TestDocumentWithOptions_Incremental(t *testing.T) {
+	fs := &recordingFileSystem{MemoryFileSystem: NewMemoryFileSystem()}
+	out := bytes.Buffer{}
+	templatesDir := writeTemplates(t, testTemplates())
+	defer os.RemoveAll(templatesDir)
+	opts := Options{
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: templatesDir,
+		FileSystem:   fs,
+		Out:          &out,
+	}
+	provider := providerFoo()
+	provider.ResourcesMap["foo_old"] = resourceFoo()
+	if errs := DocumentWithOptions(provider, opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	expected := "Documentation generated. [6] created, [0] updated, [0] " +
+		"unchanged, [0] removed.\n"
+	if out.String() != expected {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"[%s], got [%s].",
+			expected,
+			out.String(),
+		)
+	}
+
+	fs.written = nil
+	out.Reset()
+	opts.Verbose = true
+	if errs := DocumentWithOptions(providerFoo(), opts); len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	expected = "Updated: mkdocs.yml\n" +
+		"Unchanged: docs/datasources/foo_baz.md\n" +
+		"Unchanged: docs/godoc.md\n" +
+		"Unchanged: docs/index.md\n" +
+		"Unchanged: docs/resources/foo_bar.md\n" +
+		"Removed: docs/resources/foo_old.md\n" +
+		"Documentation generated. [0] created, [1] updated, [4] " +
+		"unchanged, [1] removed.\n"
+	if out.String() != expected {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"[%s], got [%s].",
+			expected,
+			out.String(),
+		)
+	}
+	// only the updated file and the manifest are written
+	if strings.Join(fs.written, ",") != "/out/mkdocs.yml,/out/.autodoc-manifest" {
+		t.Fatalf(
+			"DocumentWithOptions did not write the correct files. Expected "+
+				"[/out/mkdocs.yml /out/.autodoc-manifest], got %v.",
+			fs.written,
+		)
+	}
+}
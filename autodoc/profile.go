@@ -0,0 +1,238 @@
+package autodoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the output profiles, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Output profiles. The profile selects the site generator, which determines
+// the layout of the generated documentation files, the templates used to
+// render them, their front matter, and the site configuration files.
+const (
+	// mkdocs site: mkdocs.yml, godoc.md, and the provider, resource, and data
+	// source pages rendered from the templates directory
+	ProfileMkdocs = "mkdocs"
+	// Terraform Registry layout: docs/index.md, docs/resources/*.md and
+	// docs/data-sources/*.md with YAML front matter, rendered from built-in
+	// templates. Pages are named without the provider prefix.
+	ProfileRegistry = "registry"
+	// Hugo site: hugo.toml with the main menu, and pages with YAML front
+	// matter. The provider page is the _index.md of the content directory.
+	ProfileHugo = "hugo"
+	// Docusaurus site: sidebars.js with the sidebar, and pages with YAML
+	// front matter. The provider page is served at the root of the docs.
+	ProfileDocusaurus = "docusaurus"
+	// Plain Markdown pages without site configuration or front matter. The
+	// provider page is README.md and links to every other page.
+	ProfileBare = "bare"
+)
+
+// Built-in template associations for the registry profile. The template
+// extension from the options is appended to these names, so a user template
+// of the same name over-rides the built-in one.
+const (
+	// Template for the provider page
+	registryIndexMdTemplate = "registry-index.md"
+	// Template for all resource and data source pages
+	registrySchemaMdTemplate = "registry-schema.md"
+)
+
+// -----------------------------------------------------------------------------
+// Terraform Registry
+// -----------------------------------------------------------------------------
+
+// Terraform Registry layout. The registry builds its own navigation, so there
+// are no site configuration files.
+type registrySite struct{}
+
+func (registrySite) pageTemplate(schemaType int) string {
+	if schemaType == typeProvider {
+		return registryIndexMdTemplate
+	}
+	return registrySchemaMdTemplate
+}
+
+func (registrySite) pagePath(schemaType int, name string) string {
+	switch schemaType {
+	case typeResource:
+		return "resources/" + shortName(name) + ".md"
+	case typeDataSource:
+		return "data-sources/" + shortName(name) + ".md"
+	case typeGuide:
+		return guidePagePath(name)
+	case typeGoPackage:
+		return goPackagePagePath(name)
+	}
+	return "index.md"
+}
+
+func (registrySite) pageDirs() []string {
+	return []string{"resources", "data-sources"}
+}
+
+func (registrySite) frontMatter(data schemaDocData) string {
+	return registryFrontMatter(data)
+}
+
+func (registrySite) admonition(kind string, text string) string {
+	// the registry marks notes with "->" and warnings with "~>"
+	if kind == "warning" {
+		return "~> " + text
+	}
+	return "-> " + text
+}
+
+func (registrySite) siteFiles(opts Options) []siteFile {
+	return []siteFile{}
+}
+
+// -----------------------------------------------------------------------------
+// Registry Utility Functions
+// -----------------------------------------------------------------------------
+
+// shortName returns the name of a resource or data source without its
+// provider prefix (ie: "foo_bar_baz" => "bar_baz")
+func shortName(name string) string {
+	if idx := strings.Index(name, "_"); idx >= 0 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// providerType returns the type name of the provider in the config (ie:
+// "foo" for a provider with the resource "foo_bar"). It is inferred from the
+// provider prefix of its resources and data sources. The empty string is
+// returned if the provider has neither.
+func providerType(provider *schema.Provider) string {
+	names := []string{}
+	for name := range provider.ResourcesMap {
+		names = append(names, name)
+	}
+	for name := range provider.DataSourcesMap {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	if idx := strings.Index(names[0], "_"); idx >= 0 {
+		return names[0][:idx]
+	}
+	return names[0]
+}
+
+// providerTitle returns the title of the provider page: the provider name
+// followed by "Provider", unless the name already ends with it (ie: the
+// default name "Terraform Provider")
+func providerTitle(name string) string {
+	if strings.HasSuffix(strings.ToLower(name), "provider") {
+		return name
+	}
+	return name + " Provider"
+}
+
+// registryFrontMatter returns the YAML front matter of a Terraform Registry
+// page
+func registryFrontMatter(data schemaDocData) string {
+	title := providerTitle(data.Name)
+	switch data.SchemaType {
+	case typeResource:
+		title = data.Name + " Resource - " + data.ProviderName
+	case typeDataSource:
+		title = data.Name + " Data Source - " + data.ProviderName
+	case typeGuide, typeGoPackage:
+		title = data.Name
+	}
+	description := `""`
+	if data.Meta.Summary != "" {
+		description = "|-\n  " + strings.Replace(data.Meta.Summary, "\n", "\n  ", -1)
+	}
+	return fmt.Sprintf(
+		"---\npage_title: %q\nsubcategory: %q\ndescription: %s\n---\n",
+		title,
+		data.Meta.Category,
+		description,
+	)
+}
+
+// -----------------------------------------------------------------------------
+// Built-in Registry Templates
+// -----------------------------------------------------------------------------
+
+// Body of the built-in registry provider page
+const registryIndexMd = `{{.FrontMatter}}
+# {{providerTitle .Name}}
+
+{{if .Meta.Summary}}{{.Meta.Summary}}
+
+{{end -}}
+## Example Usage
+
+{{template "_example.template" .}}
+{{if .Arguments}}
+## Argument Reference
+
+The following arguments are supported:
+
+{{range .Arguments}}* ` + "`{{.Name}}`" + ` - ({{if .Optional}}Optional{{else}}Required{{end}}{{if .Sensitive}}, Sensitive{{end}}) {{.Description}}{{if .Anchor}} (see [below for nested schema](#{{.Anchor}})){{end}}{{template "autodoc.constraints" .}}
+{{end}}{{end}}{{template "_blocks.template" .}}`
+
+// Body of the built-in registry resource and data source page
+const registrySchemaMd = `{{.FrontMatter}}
+{{- $resource := eq .SchemaType .Constants.TypeResource}}
+# {{.Name}} ({{if $resource}}Resource{{else}}Data Source{{end}})
+
+{{if .Meta.Summary}}{{.Meta.Summary}}
+
+{{end -}}
+{{if .Meta.Deprecated}}~> **Deprecated:** {{.Meta.Deprecated}}
+
+{{end -}}
+## Example Usage
+
+{{template "_example.template" .}}
+
+## Argument Reference
+
+{{if .Arguments}}The following arguments are supported:
+
+{{range .Arguments}}* ` + "`{{.Name}}`" + ` - ({{if .Optional}}Optional{{else}}Required{{end}}{{if .ForceNew}}, Forces new resource{{end}}{{if .Sensitive}}, Sensitive{{end}}) {{.Description}}{{if .Anchor}} (see [below for nested schema](#{{.Anchor}})){{end}}{{template "autodoc.constraints" .}}
+{{end}}{{else}}This {{if $resource}}resource{{else}}data source{{end}} has no arguments.
+{{end}}
+## Attributes Reference
+
+In addition to all arguments above, the following attributes are exported:
+
+{{range .Attributes}}* <a id="{{.AttributeAnchor}}"></a>` + "`{{.Name}}`" + ` - {{if .Sensitive}}(Sensitive) {{end}}{{.Description}}{{if .Anchor}} (see [below for nested schema](#{{.Anchor}})){{end}}{{if .Deprecated}} **Deprecated:** {{.Deprecated}}{{end}}
+{{end}}{{template "_blocks.template" .}}
+{{- if .Timeouts}}
+## Timeouts
+
+The ` + "`timeouts`" + ` block allows you to specify [timeouts](https://www.terraform.io/docs/configuration/resources.html#operation-timeouts) for certain operations:
+
+{{range .Timeouts}}* ` + "`{{.Name}}`" + ` - (Defaults to {{.Default}}) {{.Description}}
+{{end}}{{end}}
+{{- if .Importable}}
+## Import
+
+` + "`{{.Name}}`" + ` can be imported using {{if .ImportIDFormat}}an ID of the form ` + "`{{.ImportIDFormat}}`" + `{{else}}its ID{{end}}:
+
+` + "```shell" + `
+terraform import {{.Name}}.example {{if .ImportIDFormat}}{{.ImportIDFormat}}{{else}}<id>{{end}}
+` + "```" + `
+{{end}}
+{{- if .ReferencedBy}}
+## Referenced By
+
+{{range .ReferencedBy}}* [` + "`{{.Name}}`" + `]({{.Path}})
+{{end}}{{end}}`
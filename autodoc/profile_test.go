@@ -0,0 +1,115 @@
+package autodoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Registry Profile
+// -----------------------------------------------------------------------------
+
+// Ensures the registry profile generates the registry layout from the
+// built-in templates, without a templates directory
+func TestDocumentWithOptions_Registry(t *testing.T) {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Importer = &schema.ResourceImporter{}
+
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(provider, Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		Profile:      ProfileRegistry,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	expectedFiles := []string{
+		"/out/.autodoc-manifest",
+		"/out/docs/data-sources/baz.md",
+		"/out/docs/index.md",
+		"/out/docs/resources/bar.md",
+	}
+	actualFiles := fs.Files()
+	if strings.Join(expectedFiles, ",") != strings.Join(actualFiles, ",") {
+		t.Fatalf(
+			"DocumentWithOptions did not write the correct files. Expected "+
+				"%v, got %v.",
+			expectedFiles,
+			actualFiles,
+		)
+	}
+
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/bar.md")
+	for _, expected := range []string{
+		"---\npage_title: \"foo_bar Resource - terraform-provider-foo\"\n" +
+			"subcategory: \"\"\ndescription: \"\"\n---\n",
+		"# foo_bar (Resource)\n",
+		"resource \"foo_bar\" \"example\" {\n  name = \"bar\"\n}\n",
+		"## Argument Reference\n",
+		"## Attributes Reference\n",
+		"terraform import foo_bar.example <id>\n",
+	} {
+		if !strings.Contains(string(resourceDoc), expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Expected [%s] in output:\n%s",
+				expected,
+				string(resourceDoc),
+			)
+		}
+	}
+
+	dataSourceDoc, _ := fs.ReadFile("/out/docs/data-sources/baz.md")
+	if strings.Contains(string(dataSourceDoc), "## Import") {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. "+
+				"Unexpected import section in output:\n%s",
+			string(dataSourceDoc),
+		)
+	}
+}
+
+// Ensures the short name strips the provider prefix
+func TestShortName(t *testing.T) {
+	cases := map[string]string{
+		"foo_bar":     "bar",
+		"foo_bar_baz": "bar_baz",
+		"foo":         "foo",
+	}
+	for name, expected := range cases {
+		if actual := shortName(name); actual != expected {
+			t.Fatalf(
+				"shortName did not return the correct output. Expected "+
+					"[%s], got [%s].",
+				expected,
+				actual,
+			)
+		}
+	}
+}
+
+// Ensures the provider page title does not repeat "Provider"
+func TestProviderTitle(t *testing.T) {
+	cases := map[string]string{
+		"foo":                    "foo Provider",
+		"Terraform Provider":     "Terraform Provider",
+		"terraform-provider-foo": "terraform-provider-foo Provider",
+	}
+	for name, expected := range cases {
+		if actual := providerTitle(name); actual != expected {
+			t.Fatalf(
+				"providerTitle did not return the correct output. Expected "+
+					"[%s], got [%s].",
+				expected,
+				actual,
+			)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package autodoc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the reference syntax, be
+//   sure to update the documentation! This includes:
+//
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Reference syntax
+const (
+	// Starts a reference. The target follows in parentheses (ie:
+	// "@REF(foo_bar.id)").
+	MetaRef = "@REF"
+	// Prefix of the target of a reference to a data source (ie:
+	// "@REF(data.foo_baz.name)")
+	refDataPrefix = "data."
+	// Attribute of every resource and data source that is not in its schema
+	refIDAttribute = "id"
+)
+
+// -----------------------------------------------------------------------------
+// Reference Data Structs
+// -----------------------------------------------------------------------------
+
+// A reference from a description to a resource, a data source, or one of
+// their attributes
+type schemaRef struct {
+	// Target as written in the description (ie: "foo_bar.id")
+	target string
+	// The type of schema referenced, typeResource or typeDataSource
+	schemaType int
+	// Name of the resource or data source
+	name string
+	// Dot separated path of the attribute. Empty for references to the
+	// resource or data source itself.
+	path string
+}
+
+// A provider, resource, or data source
+type schemaObject struct {
+	// The type of schema, one of the typeXxx constants
+	schemaType int
+	// Name of the provider, resource, or data source
+	name string
+}
+
+// -----------------------------------------------------------------------------
+// Reference Utility Functions
+// -----------------------------------------------------------------------------
+
+// replaceRefs calls fn with the target of every reference in the text and
+// replaces the reference with the result. "\@REF(" is a literal "@REF(". An
+// error is returned for the first reference that is not terminated, or for
+// which fn returns an error.
+func replaceRefs(text string, fn func(target string) (string, error)) (string, error) {
+	b := strings.Builder{}
+	pos := 0
+	for pos < len(text) {
+		if text[pos] == metaEscape && isRefStart(text, pos+1) {
+			b.WriteString(MetaRef + "(")
+			pos += len(MetaRef) + 2
+			continue
+		}
+		if !isRefStart(text, pos) || (pos > 0 && isMetaWordChar(text[pos-1])) {
+			b.WriteByte(text[pos])
+			pos++
+			continue
+		}
+		start := pos + len(MetaRef) + 1
+		end := strings.IndexByte(text[start:], ')')
+		if end < 0 {
+			return b.String(), fmt.Errorf(
+				"Reference [%s] is not terminated.",
+				text[pos:],
+			)
+		}
+		replacement, fnErr := fn(text[start : start+end])
+		if fnErr != nil {
+			return b.String(), fnErr
+		}
+		b.WriteString(replacement)
+		pos = start + end + 1
+	}
+	return b.String(), nil
+}
+
+// isRefStart returns whether or not a reference starts at pos
+func isRefStart(text string, pos int) bool {
+	return strings.HasPrefix(text[pos:], MetaRef+"(")
+}
+
+// plainRefs replaces every reference in the text with its target, for
+// output that cannot link (ie: front matter). Unterminated references are
+// left as they are.
+func plainRefs(text string) string {
+	plain, refErr := replaceRefs(text, func(target string) (string, error) {
+		return target, nil
+	})
+	if refErr != nil {
+		return text
+	}
+	return plain
+}
+
+// parseRef parses the target of a reference: the name of a resource, or of a
+// data source prefixed with "data.", optionally followed by the dot separated
+// path of an attribute
+func parseRef(target string) (schemaRef, error) {
+	ref := schemaRef{target: target, schemaType: typeResource}
+	name := target
+	if strings.HasPrefix(name, refDataPrefix) {
+		ref.schemaType = typeDataSource
+		name = name[len(refDataPrefix):]
+	}
+	if idx := strings.Index(name, "."); idx >= 0 {
+		ref.path = name[idx+1:]
+		name = name[:idx]
+	}
+	ref.name = name
+	if ref.name == "" || strings.ContainsAny(target, " \t\n") {
+		return ref, fmt.Errorf("Reference [%s] is not a resource, data source, or attribute.", target)
+	}
+	return ref, nil
+}
+
+// checkRef returns an error if the resource, data source, or attribute the
+// reference names does not exist in the provider
+func checkRef(provider *schema.Provider, ref schemaRef) error {
+	resources, kind := provider.ResourcesMap, "resource"
+	if ref.schemaType == typeDataSource {
+		resources, kind = provider.DataSourcesMap, "data source"
+	}
+	resource, ok := resources[ref.name]
+	if !ok {
+		return fmt.Errorf(
+			"Reference [%s] names %s [%s], which does not exist.",
+			ref.target,
+			kind,
+			ref.name,
+		)
+	}
+	if ref.path == "" || ref.path == refIDAttribute {
+		return nil
+	}
+	if strings.Split(ref.path, ".")[0] == MetaAttribute || !schemaPathExists(resource.Schema, ref.path) {
+		return fmt.Errorf(
+			"Reference [%s] names attribute [%s] of [%s], which does not exist.",
+			ref.target,
+			ref.path,
+			ref.name,
+		)
+	}
+	return nil
+}
+
+// checkRefs returns an error for the first reference in the description that
+// cannot be parsed or names something that does not exist in the provider
+func checkRefs(provider *schema.Provider, descr string) error {
+	_, refErr := replaceRefs(descr, func(target string) (string, error) {
+		ref, parseErr := parseRef(target)
+		if parseErr != nil {
+			return "", parseErr
+		}
+		return "", checkRef(provider, ref)
+	})
+	return refErr
+}
+
+// validateRefs checks the references in the description of the resource and
+// every attribute of the schema map, and returns an error for the first one
+// that is invalid. object is the name of the provider, resource, or data
+// source; resource is nil for the provider.
+func validateRefs(provider *schema.Provider, object string, resource *schema.Resource, schemaMap map[string]*schema.Schema) error {
+	if resource != nil {
+		if refErr := checkRefs(provider, resource.Description); refErr != nil {
+			return refError(object, "", refErr)
+		}
+	}
+	var firstErr error
+	walkSchema(schemaMap, "", func(path string, s *schema.Schema) {
+		if refErr := checkRefs(provider, s.Description); refErr != nil && firstErr == nil {
+			firstErr = refError(object, path, refErr)
+		}
+	})
+	return firstErr
+}
+
+// refError returns an error for an invalid reference in the description of
+// an attribute, or of the object itself if path is empty
+func refError(object string, path string, refErr error) error {
+	if path != "" {
+		object += "." + path
+	}
+	return fmt.Errorf(
+		"Cannot resolve the references of [%s]. Error: [%s]",
+		object,
+		refErr.Error(),
+	)
+}
+
+// refLink returns a Markdown link to the target of the reference, relative
+// to the page being rendered (from, as in pageLinkPath). References to an
+// attribute link to its anchor; the id attribute is not documented, so
+// references to it link to the page.
+func refLink(site siteGenerator, from interface{}, ref schemaRef) string {
+	link := pageLinkPath(site, from, ref.schemaType, ref.name)
+	if ref.path != "" && ref.path != refIDAttribute {
+		link += "#" + attributeAnchor(ref.path)
+	}
+	return markdownLink(inlineCode(ref.target), link)
+}
+
+// linkRefs replaces every reference in the description with a link. The
+// references are expected to have been validated; invalid references are
+// left as plain text.
+func linkRefs(site siteGenerator, from interface{}, descr string) string {
+	linked, _ := replaceRefs(descr, func(target string) (string, error) {
+		ref, parseErr := parseRef(target)
+		if parseErr != nil {
+			return target, nil
+		}
+		return refLink(site, from, ref), nil
+	})
+	return linked
+}
+
+// linkDocRefs replaces the references in every description of the page data
+// with links relative to the page
+func linkDocRefs(site siteGenerator, data *schemaDocData) {
+	data.Meta.Summary = linkRefs(site, *data, data.Meta.Summary)
+	linkAttributeRefs(site, *data, data.Attributes, data.Arguments)
+	linkBlockRefs(site, *data, data.Blocks)
+}
+
+// linkAttributeRefs links the references in the descriptions of the
+// attributes and arguments
+func linkAttributeRefs(site siteGenerator, from interface{}, attrs []schemaAttribute, args []schemaArgument) {
+	for idx := range attrs {
+		attrs[idx].Description = linkRefs(site, from, attrs[idx].Description)
+	}
+	for idx := range args {
+		args[idx].Description = linkRefs(site, from, args[idx].Description)
+	}
+}
+
+// linkBlockRefs links the references in the descriptions of the blocks and
+// everything nested under them
+func linkBlockRefs(site siteGenerator, from interface{}, blocks []schemaBlock) {
+	for idx := range blocks {
+		blocks[idx].Description = linkRefs(site, from, blocks[idx].Description)
+		linkAttributeRefs(site, from, blocks[idx].Attributes, blocks[idx].Arguments)
+		linkBlockRefs(site, from, blocks[idx].Blocks)
+	}
+}
+
+// providerReferences returns, for every resource and data source, the
+// provider, resources, and data sources whose descriptions reference it or
+// its attributes. Each list is sorted by name. Invalid references and
+// references of an object to itself are ignored.
+func providerReferences(provider *schema.Provider) map[schemaObject][]schemaObject {
+	referrers := map[schemaObject]map[schemaObject]bool{}
+	collect := func(from schemaObject, descr string) {
+		replaceRefs(descr, func(target string) (string, error) {
+			ref, parseErr := parseRef(target)
+			if parseErr != nil || checkRef(provider, ref) != nil {
+				return "", nil
+			}
+			to := schemaObject{schemaType: ref.schemaType, name: ref.name}
+			if to == from {
+				return "", nil
+			}
+			if referrers[to] == nil {
+				referrers[to] = map[schemaObject]bool{}
+			}
+			referrers[to][from] = true
+			return "", nil
+		})
+	}
+	collectSchema := func(from schemaObject, resource *schema.Resource, schemaMap map[string]*schema.Schema) {
+		if resource != nil {
+			collect(from, resource.Description)
+		}
+		walkSchema(schemaMap, "", func(path string, s *schema.Schema) {
+			collect(from, s.Description)
+		})
+	}
+
+	collectSchema(schemaObject{schemaType: typeProvider}, nil, provider.Schema)
+	for name, resource := range provider.ResourcesMap {
+		collectSchema(schemaObject{schemaType: typeResource, name: name}, resource, resource.Schema)
+	}
+	for name, resource := range provider.DataSourcesMap {
+		collectSchema(schemaObject{schemaType: typeDataSource, name: name}, resource, resource.Schema)
+	}
+
+	references := map[schemaObject][]schemaObject{}
+	for to, from := range referrers {
+		for object := range from {
+			references[to] = append(references[to], object)
+		}
+		sort.Slice(references[to], func(i, j int) bool {
+			a, b := references[to][i], references[to][j]
+			if a.name != b.name {
+				return a.name < b.name
+			}
+			return a.schemaType < b.schemaType
+		})
+	}
+	return references
+}
+
+// schemaReferences returns the template data of the pages that reference the
+// page being rendered. The provider is named after the options' provider
+// name.
+func schemaReferences(site siteGenerator, from schemaDocData, referrers []schemaObject) []schemaReference {
+	references := []schemaReference{}
+	for _, referrer := range referrers {
+		name := referrer.name
+		if referrer.schemaType == typeProvider {
+			name = from.ProviderName
+		}
+		references = append(references, schemaReference{
+			Name:       name,
+			SchemaType: referrer.schemaType,
+			Path:       pageLinkPath(site, from, referrer.schemaType, name),
+		})
+	}
+	return references
+}
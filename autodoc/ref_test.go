@@ -0,0 +1,189 @@
+package autodoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// References
+// -----------------------------------------------------------------------------
+
+// providerRefs returns a provider whose resource and data source reference
+// each other
+func providerRefs() *schema.Provider {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Description = "A bar. See @REF(data.foo_baz)."
+	provider.DataSourcesMap["foo_baz"].Schema["name"].Description =
+		"Name of the @REF(foo_bar.rule.priority) rule, or @REF(foo_bar.id)."
+	return provider
+}
+
+// Ensures references are found in the text and escaped references are left
+// as text
+func TestReplaceRefs(t *testing.T) {
+	cases := map[string]string{
+		"See @REF(foo_bar).":              "See <foo_bar>.",
+		"(@REF(foo_bar.id))":              "(<foo_bar.id>)",
+		"@REF(a) and @REF(data.b.c)":      "<a> and <data.b.c>",
+		`Literal \@REF(foo_bar).`:         "Literal @REF(foo_bar).",
+		"user@REF(foo_bar) is not one":    "user@REF(foo_bar) is not one",
+		"@REF without parentheses @REFER": "@REF without parentheses @REFER",
+	}
+	for text, expected := range cases {
+		actual, refErr := replaceRefs(text, func(target string) (string, error) {
+			return "<" + target + ">", nil
+		})
+		if refErr != nil || actual != expected {
+			t.Fatalf(
+				"replaceRefs did not return the correct output for [%s]. "+
+					"Expected [%s], got [%s] (%v).",
+				text,
+				expected,
+				actual,
+				refErr,
+			)
+		}
+	}
+
+	if _, refErr := replaceRefs("See @REF(foo_bar", nil); refErr == nil {
+		t.Fatalf("replaceRefs did not return an error for an unterminated reference.")
+	}
+	// references are not metadata tags, and stay in the description text
+	parsed, _ := parseDescription(`See @REF(foo_bar) or \@REF(x). @IMMUTABLE`)
+	if len(parsed.unknown) != 0 || parsed.text != `See @REF(foo_bar) or \@REF(x).` {
+		t.Fatalf(
+			"parseDescription did not return the correct output. Expected the "+
+				"references in the text, got [%s] with unknown tags %v.",
+			parsed.text,
+			parsed.unknown,
+		)
+	}
+}
+
+// Ensures references to resources, data sources, or attributes that do not
+// exist are errors
+func TestCheckRefs(t *testing.T) {
+	provider := providerFoo()
+	cases := map[string]string{
+		"@REF(foo_bar)":                   "",
+		"@REF(foo_bar.id)":                "",
+		"@REF(foo_bar.rule.0.priority)":   "",
+		"@REF(data.foo_baz.status.state)": "",
+		"@REF(foo_nope)":                  "Reference [foo_nope] names resource [foo_nope], which does not exist.",
+		"@REF(data.foo_bar)":              "Reference [data.foo_bar] names data source [foo_bar], which does not exist.",
+		"@REF(foo_bar.rule.weight)":       "Reference [foo_bar.rule.weight] names attribute [rule.weight] of [foo_bar], which does not exist.",
+		"@REF()":                          "Reference [] is not a resource, data source, or attribute.",
+	}
+	for descr, expected := range cases {
+		actual := ""
+		if refErr := checkRefs(provider, descr); refErr != nil {
+			actual = refErr.Error()
+		}
+		if actual != expected {
+			t.Fatalf(
+				"checkRefs did not return the correct error for [%s]. "+
+					"Expected [%s], got [%s].",
+				descr,
+				expected,
+				actual,
+			)
+		}
+	}
+}
+
+// Ensures references are rendered as links relative to the page, and each
+// page lists the pages that reference it
+func TestDocumentWithOptions_Refs(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(providerRefs(), Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+
+	expectSiteFiles(t, fs, fs.Files(), map[string][]string{
+		"/out/docs/resources/foo_bar.md": {
+			"A bar. See [`data.foo_baz`](../datasources/foo_baz.md).\n",
+			"* <a id=\"attr-rule-priority\"></a>`priority` - `schema.TypeInt`.\n",
+			"## Referenced By\n\n* [`foo_baz`](../datasources/foo_baz.md)\n",
+		},
+		"/out/docs/datasources/foo_baz.md": {
+			"Name of the [`foo_bar.rule.priority`](../resources/foo_bar.md#attr-rule-priority) " +
+				"rule, or [`foo_bar.id`](../resources/foo_bar.md).",
+			"## Referenced By\n\n* [`foo_bar`](../resources/foo_bar.md)\n",
+		},
+	})
+}
+
+// Ensures front matter shows references as plain text
+func TestDocumentWithOptions_RefsFrontMatter(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(providerRefs(), Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		Profile:      ProfileHugo,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	expectSiteFiles(t, fs, fs.Files(), map[string][]string{
+		"/out/docs/resources/foo_bar.md": {
+			"description: \"A bar. See data.foo_baz.\"\n",
+		},
+	})
+}
+
+// Ensures a reference to an attribute that does not exist fails the
+// generation and is reported by the linter
+func TestDocumentWithOptions_InvalidRef(t *testing.T) {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Schema["name"].Description = "See @REF(foo_bar.nope)."
+
+	errs := DocumentWithOptions(provider, Options{
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		FileSystem:   NewMemoryFileSystem(),
+	})
+	expected := "Cannot resolve the references of [foo_bar.name]. Error: " +
+		"[Reference [foo_bar.nope] names attribute [nope] of [foo_bar], " +
+		"which does not exist.]"
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Expected "+
+				"[%s], got %v.",
+			expected,
+			errs,
+		)
+	}
+
+	out := &bytes.Buffer{}
+	DocumentWithOptions(provider, Options{
+		Lint:       true,
+		LintRules:  map[string]LintSeverity{LintMissingSummary: LintSeverityOff},
+		Out:        out,
+		FileSystem: NewMemoryFileSystem(),
+	})
+	expected = "foo_bar.name: error: Reference [foo_bar.nope] names attribute " +
+		"[nope] of [foo_bar], which does not exist. [invalid-ref]\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. Expected "+
+				"[%s] in output:\n%s",
+			expected,
+			out.String(),
+		)
+	}
+}
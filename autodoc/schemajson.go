@@ -0,0 +1,383 @@
+package autodoc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the JSON schema format, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+//
+//   Schema snapshots are read back by the changelog and baseline modes, so
+//   fields should only ever be added.
+
+// Format version of the JSON schema document. This matches the format version
+// of `terraform providers schema -json`.
+const schemaJSONFormatVersion = "0.1"
+
+// Output path denoting the options' output instead of a file
+const stdoutPath = "-"
+
+// -----------------------------------------------------------------------------
+// JSON Schema Definition - The document has the shape of the output of
+//   `terraform providers schema -json`. autodoc metadata that Terraform does
+//   not have is added under "autodoc" keys.
+// -----------------------------------------------------------------------------
+
+// The JSON schema document of a provider
+type providerSchemasJSON struct {
+	FormatVersion string `json:"format_version"`
+	// Schema of the provider by its type name in the config
+	ProviderSchemas map[string]*providerSchemaJSON `json:"provider_schemas"`
+}
+
+// The schema of a provider, its resources, and its data sources
+type providerSchemaJSON struct {
+	Provider          *schemaJSON            `json:"provider"`
+	ResourceSchemas   map[string]*schemaJSON `json:"resource_schemas,omitempty"`
+	DataSourceSchemas map[string]*schemaJSON `json:"data_source_schemas,omitempty"`
+}
+
+// The schema of a provider, resource, or data source
+type schemaJSON struct {
+	Version int        `json:"version"`
+	Block   *blockJSON `json:"block"`
+	// autodoc metadata of a resource or data source
+	Autodoc *schemaAutodocJSON `json:"autodoc,omitempty"`
+}
+
+// A configuration block: the root of a schema or a nested block
+type blockJSON struct {
+	Attributes      map[string]*attributeJSON `json:"attributes,omitempty"`
+	BlockTypes      map[string]*blockTypeJSON `json:"block_types,omitempty"`
+	Description     string                    `json:"description,omitempty"`
+	DescriptionKind string                    `json:"description_kind,omitempty"`
+	Deprecated      bool                      `json:"deprecated,omitempty"`
+}
+
+// An attribute of a block
+type attributeJSON struct {
+	// Type of the attribute in the cty JSON type format (ie: "string",
+	// ["list", "number"])
+	AttributeType   json.RawMessage `json:"type"`
+	Description     string          `json:"description,omitempty"`
+	DescriptionKind string          `json:"description_kind,omitempty"`
+	Required        bool            `json:"required,omitempty"`
+	Optional        bool            `json:"optional,omitempty"`
+	Computed        bool            `json:"computed,omitempty"`
+	Sensitive       bool            `json:"sensitive,omitempty"`
+	Deprecated      bool            `json:"deprecated,omitempty"`
+	// autodoc metadata of the attribute
+	Autodoc *attributeAutodocJSON `json:"autodoc,omitempty"`
+}
+
+// A nested block type of a block
+type blockTypeJSON struct {
+	NestingMode string     `json:"nesting_mode"`
+	Block       *blockJSON `json:"block"`
+	MinItems    int        `json:"min_items,omitempty"`
+	MaxItems    int        `json:"max_items,omitempty"`
+	// autodoc metadata of the nested block
+	Autodoc *attributeAutodocJSON `json:"autodoc,omitempty"`
+}
+
+// autodoc metadata of a resource or data source
+type schemaAutodocJSON struct {
+	Summary        string            `json:"summary,omitempty"`
+	Deprecated     string            `json:"deprecated,omitempty"`
+	Category       string            `json:"category,omitempty"`
+	Uncreatable    bool              `json:"uncreatable,omitempty"`
+	Undeletable    bool              `json:"undeletable,omitempty"`
+	Immutable      bool              `json:"immutable,omitempty"`
+	Importable     bool              `json:"importable,omitempty"`
+	ImportIDFormat string            `json:"import_id_format,omitempty"`
+	Timeouts       map[string]string `json:"timeouts,omitempty"`
+	// Values of the custom tags by tag name
+	Custom map[string]string `json:"custom,omitempty"`
+}
+
+// autodoc metadata of an attribute or nested block
+type attributeAutodocJSON struct {
+	// Schema type of the attribute (ie: "TypeList")
+	SchemaType    string   `json:"schema_type"`
+	Example       string   `json:"example,omitempty"`
+	Default       string   `json:"default,omitempty"`
+	ForceNew      bool     `json:"force_new,omitempty"`
+	Unexported    bool     `json:"unexported,omitempty"`
+	Deprecated    string   `json:"deprecated,omitempty"`
+	MinItems      int      `json:"min_items,omitempty"`
+	MaxItems      int      `json:"max_items,omitempty"`
+	ConfigMode    string   `json:"config_mode,omitempty"`
+	ConflictsWith []string `json:"conflicts_with,omitempty"`
+	ExactlyOneOf  []string `json:"exactly_one_of,omitempty"`
+	AtLeastOneOf  []string `json:"at_least_one_of,omitempty"`
+	RequiredWith  []string `json:"required_with,omitempty"`
+	ComputedWhen  []string `json:"computed_when,omitempty"`
+	// Values of the custom tags by tag name
+	Custom map[string]string `json:"custom,omitempty"`
+}
+
+// -----------------------------------------------------------------------------
+// JSON Schema Utility Functions
+// -----------------------------------------------------------------------------
+
+// exportSchemaJSON writes the JSON schema document of the provider to the
+// path in the options, or to the options' output if the path is "-".
+// Returns a list of errors. If this list is empty, the document was written.
+func exportSchemaJSON(provider *schema.Provider, opts Options) []error {
+	errors := []error{}
+
+	content, marshalErr := marshalSchemaJSON(providerSchemas(provider, opts))
+	if marshalErr != nil {
+		errors = append(errors, marshalErr)
+		return errors
+	}
+
+	if opts.SchemaJSON == stdoutPath {
+		if _, writeErr := opts.Out.Write(content); writeErr != nil {
+			errors = append(errors, writeErr)
+		}
+		return errors
+	}
+	if writeErr := opts.FileSystem.WriteFile(opts.SchemaJSON, content, outputFileMode); writeErr != nil {
+		errors = append(errors, fmt.Errorf(
+			"Cannot generate [%s]. Failed to write file. Error: [%s]",
+			opts.SchemaJSON,
+			writeErr.Error(),
+		))
+	}
+	return errors
+}
+
+// marshalSchemaJSON encodes the JSON schema document as indented JSON with a
+// trailing newline. Keys are sorted, so the encoding is stable.
+func marshalSchemaJSON(doc *providerSchemasJSON) ([]byte, error) {
+	content, marshalErr := json.MarshalIndent(doc, "", "  ")
+	if marshalErr != nil {
+		return nil, fmt.Errorf(
+			"Cannot encode the provider schema. Error: [%s]",
+			marshalErr.Error(),
+		)
+	}
+	return append(content, '\n'), nil
+}
+
+// providerSchemas builds the JSON schema document of the provider. The
+// provider is keyed by its type name in the config, or by the provider name
+// from the options if it has no resources or data sources.
+func providerSchemas(provider *schema.Provider, opts Options) *providerSchemasJSON {
+	name := providerType(provider)
+	if name == "" {
+		name = opts.ProviderName
+	}
+
+	providerJSON := &providerSchemaJSON{
+		Provider: &schemaJSON{
+			Block: blockSchemaJSON(provider.Schema),
+		},
+		ResourceSchemas:   map[string]*schemaJSON{},
+		DataSourceSchemas: map[string]*schemaJSON{},
+	}
+	for resourceName, resource := range provider.ResourcesMap {
+		providerJSON.ResourceSchemas[resourceName] = resourceSchemaJSON(typeResource, resource)
+	}
+	for dataSourceName, dataSource := range provider.DataSourcesMap {
+		providerJSON.DataSourceSchemas[dataSourceName] = resourceSchemaJSON(typeDataSource, dataSource)
+	}
+
+	return &providerSchemasJSON{
+		FormatVersion: schemaJSONFormatVersion,
+		ProviderSchemas: map[string]*providerSchemaJSON{
+			name: providerJSON,
+		},
+	}
+}
+
+// resourceSchemaJSON builds the JSON schema of a resource or data source.
+// schemaType should be typeResource or typeDataSource. Like Terraform, the id
+// attribute and the timeouts block are added when the SDK adds them.
+func resourceSchemaJSON(schemaType int, resource *schema.Resource) *schemaJSON {
+	meta := parseMeta(schemaType, resource, resource.Schema)
+	block := blockSchemaJSON(resource.Schema)
+	block.Description = plainRefs(meta.Summary)
+	if block.Description != "" {
+		block.DescriptionKind = "plain"
+	}
+	block.Deprecated = meta.Deprecated != ""
+
+	if _, ok := block.Attributes["id"]; !ok {
+		block.Attributes["id"] = &attributeJSON{
+			AttributeType: ctyTypeJSON("string"),
+			Optional:      true,
+			Computed:      true,
+		}
+	}
+
+	autodoc := &schemaAutodocJSON{
+		Summary:        plainRefs(meta.Summary),
+		Deprecated:     meta.Deprecated,
+		Category:       meta.Category,
+		Uncreatable:    meta.Uncreatable,
+		Undeletable:    meta.Undeletable,
+		Immutable:      meta.Immutable,
+		Importable:     resource.Importer != nil,
+		ImportIDFormat: parseImportID(resource, resource.Schema),
+		Custom:         meta.Custom,
+	}
+	if timeouts := schemaTimeouts(resource.Timeouts); len(timeouts) > 0 {
+		timeoutsBlock := &blockJSON{Attributes: map[string]*attributeJSON{}}
+		autodoc.Timeouts = map[string]string{}
+		for _, timeout := range timeouts {
+			timeoutsBlock.Attributes[timeout.Name] = &attributeJSON{
+				AttributeType: ctyTypeJSON("string"),
+				Optional:      true,
+			}
+			autodoc.Timeouts[timeout.Name] = timeout.Default
+		}
+		block.BlockTypes[schema.TimeoutsConfigKey] = &blockTypeJSON{
+			NestingMode: "single",
+			Block:       timeoutsBlock,
+		}
+	}
+
+	return &schemaJSON{
+		Version: resource.SchemaVersion,
+		Block:   block,
+		Autodoc: autodoc,
+	}
+}
+
+// blockSchemaJSON builds the JSON schema of the block defined by the schema
+// map. Nested blocks are walked to arbitrary depth. As in Terraform, nested
+// blocks in attribute syntax and Computed only nested blocks are attributes
+// with a list or set of objects type.
+func blockSchemaJSON(schemaMap map[string]*schema.Schema) *blockJSON {
+	block := &blockJSON{
+		Attributes: map[string]*attributeJSON{},
+		BlockTypes: map[string]*blockTypeJSON{},
+	}
+	for name, s := range schemaMap {
+		if name == MetaAttribute {
+			continue
+		}
+		if isBlock(s) && !isAttributeBlock(s) {
+			blockType := &blockTypeJSON{
+				NestingMode: "list",
+				Block:       blockSchemaJSON(s.Elem.(*schema.Resource).Schema),
+				MinItems:    s.MinItems,
+				MaxItems:    s.MaxItems,
+				Autodoc:     attributeAutodoc(s),
+			}
+			if s.Type == schema.TypeSet {
+				blockType.NestingMode = "set"
+			}
+			blockType.Block.Description = plainRefs(stripMeta(s.Description))
+			if blockType.Block.Description != "" {
+				blockType.Block.DescriptionKind = "plain"
+			}
+			blockType.Block.Deprecated = s.Deprecated != ""
+			block.BlockTypes[name] = blockType
+			continue
+		}
+		attr := &attributeJSON{
+			AttributeType: ctyTypeJSON(ctyType(s)),
+			Description:   plainRefs(stripMeta(s.Description)),
+			Required:      s.Required,
+			Optional:      s.Optional,
+			Computed:      s.Computed,
+			Sensitive:     s.Sensitive,
+			Deprecated:    s.Deprecated != "",
+			Autodoc:       attributeAutodoc(s),
+		}
+		if attr.Description != "" {
+			attr.DescriptionKind = "plain"
+		}
+		block.Attributes[name] = attr
+	}
+	return block
+}
+
+// attributeAutodoc returns the autodoc metadata of an attribute or nested
+// block
+func attributeAutodoc(s *schema.Schema) *attributeAutodocJSON {
+	parsed, _ := parseDescription(s.Description)
+	autodoc := &attributeAutodocJSON{
+		SchemaType:    s.Type.String(),
+		Example:       parsed.value(MetaExample),
+		ForceNew:      s.ForceNew,
+		Unexported:    parsed.has(MetaUnexported),
+		Custom:        customTags(parsed),
+		Deprecated:    s.Deprecated,
+		MinItems:      s.MinItems,
+		MaxItems:      s.MaxItems,
+		ConflictsWith: s.ConflictsWith,
+		ExactlyOneOf:  s.ExactlyOneOf,
+		AtLeastOneOf:  s.AtLeastOneOf,
+		RequiredWith:  s.RequiredWith,
+		ComputedWhen:  s.ComputedWhen,
+	}
+	if s.Default != nil {
+		autodoc.Default = hclLiteral(s.Default)
+	}
+	if s.ConfigMode != schema.SchemaConfigModeAuto {
+		autodoc.ConfigMode = configMode(s.ConfigMode)
+	}
+	return autodoc
+}
+
+// isAttributeBlock returns whether or not a nested block is represented as
+// an attribute: it is in attribute syntax, or it is Computed only.
+func isAttributeBlock(s *schema.Schema) bool {
+	if s.ConfigMode == schema.SchemaConfigModeAttr {
+		return true
+	}
+	return s.ConfigMode == schema.SchemaConfigModeAuto && s.Computed && !s.Optional
+}
+
+// ctyType returns the type of the schema in the cty JSON type format, as a
+// Go value to be encoded (ie: "string", []interface{}{"list", "number"}).
+// Nested blocks are lists or sets of objects.
+func ctyType(s *schema.Schema) interface{} {
+	switch s.Type {
+	case schema.TypeBool:
+		return "bool"
+	case schema.TypeInt, schema.TypeFloat:
+		return "number"
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		collection := "list"
+		if s.Type == schema.TypeSet {
+			collection = "set"
+		} else if s.Type == schema.TypeMap {
+			collection = "map"
+		}
+		switch elem := s.Elem.(type) {
+		case *schema.Schema:
+			return []interface{}{collection, ctyType(elem)}
+		case *schema.Resource:
+			// a map of resources is a map of strings in Terraform
+			if s.Type == schema.TypeMap {
+				break
+			}
+			attrs := map[string]interface{}{}
+			for name, attrSchema := range elem.Schema {
+				attrs[name] = ctyType(attrSchema)
+			}
+			return []interface{}{collection, []interface{}{"object", attrs}}
+		}
+		return []interface{}{collection, "string"}
+	default:
+		return "string"
+	}
+}
+
+// ctyTypeJSON encodes a cty JSON type
+func ctyTypeJSON(t interface{}) json.RawMessage {
+	// cty types are built from strings, slices, and maps, which always encode
+	encoded, _ := json.Marshal(t)
+	return encoded
+}
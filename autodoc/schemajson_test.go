@@ -0,0 +1,83 @@
+package autodoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// -----------------------------------------------------------------------------
+// JSON Schema
+// -----------------------------------------------------------------------------
+
+// Ensures the JSON schema document has the shape of `terraform providers
+// schema -json` with the autodoc metadata added
+func TestDocumentWithOptions_SchemaJSON(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:    "/out",
+		SchemaJSON: "/out/schema.json",
+		FileSystem: fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	if files := fs.Files(); strings.Join(files, ",") != "/out/schema.json" {
+		t.Fatalf(
+			"DocumentWithOptions did not write the correct files. Expected "+
+				"[/out/schema.json], got %v.",
+			files,
+		)
+	}
+
+	content, _ := fs.ReadFile("/out/schema.json")
+	doc := providerSchemasJSON{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("The JSON schema does not decode: [%s]", err)
+	}
+	if doc.FormatVersion != schemaJSONFormatVersion {
+		t.Fatalf(
+			"The JSON schema has the wrong format version. Expected [%s], "+
+				"got [%s].",
+			schemaJSONFormatVersion,
+			doc.FormatVersion,
+		)
+	}
+	provider, ok := doc.ProviderSchemas["foo"]
+	if !ok || provider.ResourceSchemas["foo_bar"] == nil || provider.DataSourceSchemas["foo_baz"] == nil {
+		t.Fatalf("The JSON schema is missing schemas. Got:\n%s", content)
+	}
+
+	block := provider.ResourceSchemas["foo_bar"].Block
+	name := block.Attributes["name"]
+	if string(name.AttributeType) != `"string"` || !name.Required ||
+		name.Description != "Name of the foo" || name.Autodoc.Example != "bar" {
+		t.Fatalf("The JSON schema has the wrong name attribute. Got [%+v].", name)
+	}
+	if id := block.Attributes["id"]; id == nil || !id.Optional || !id.Computed {
+		t.Fatalf("The JSON schema is missing the id attribute. Got:\n%s", content)
+	}
+
+	rule := block.BlockTypes["rule"]
+	if rule == nil || rule.NestingMode != "list" || rule.MinItems != 1 || rule.MaxItems != 3 {
+		t.Fatalf("The JSON schema has the wrong rule block. Got [%+v].", rule)
+	}
+	if condition := rule.Block.BlockTypes["condition"]; condition == nil || condition.NestingMode != "set" {
+		t.Fatalf("The JSON schema is missing the nested condition block. Got:\n%s", content)
+	}
+
+	// Computed only blocks are attributes of a list of objects
+	status := block.Attributes["status"]
+	if status == nil {
+		t.Fatalf("The JSON schema is missing the status attribute. Got:\n%s", content)
+	}
+	statusType := bytes.Buffer{}
+	json.Compact(&statusType, status.AttributeType)
+	if statusType.String() != `["list",["object",{"state":"string"}]]` {
+		t.Fatalf(
+			"The JSON schema has the wrong status attribute type. Got [%s].",
+			statusType.String(),
+		)
+	}
+}
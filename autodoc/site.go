@@ -0,0 +1,653 @@
+package autodoc
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// NOTE(ALL): If you make modifications to the site generators, be
+//   sure to update the documentation! This includes:
+//
+//   * The package comment in autodoc.go
+//   * The Usage() function in autodoc.go
+//   * The autodoc tool documentation in docs/autodoc.md
+
+// Built-in template associations for the site generators. The template
+// extension from the options is appended to these names, so a user template
+// of the same name over-rides the built-in one.
+const (
+	// Template for the Hugo site configuration
+	hugoTomlTemplate = "hugo.toml"
+	// Template for the Docusaurus sidebar
+	sidebarsJsTemplate = "sidebars.js"
+	// Template for the provider page of the bare Markdown layout
+	bareIndexMdTemplate = "bare-index.md"
+)
+
+// -----------------------------------------------------------------------------
+// Site Generator Definition
+// -----------------------------------------------------------------------------
+
+// A siteGenerator lays out the documentation for a static site generator. It
+// names the page files, renders their front matter, and lists the site
+// configuration files (ie: mkdocs.yml) that hold the navigation.
+type siteGenerator interface {
+	// Returns the template association (without the template extension) of
+	// the provider, resource, or data source pages. schemaType is one of the
+	// typeXxx constants.
+	pageTemplate(schemaType int) string
+	// Returns the path of the page documenting the provider, resource, or
+	// data source, relative to the docs directory and separated by forward
+	// slashes
+	pagePath(schemaType int, name string) string
+	// Returns the directories, relative to the docs directory, that contain
+	// one page per resource or data source
+	pageDirs() []string
+	// Returns the front matter of a page, including the trailing newline, or
+	// the empty string if the pages have no front matter
+	frontMatter(data schemaDocData) string
+	// Returns the text formatted as a call-out box of the kind ("note" or
+	// "warning") in the Markdown dialect of the site
+	admonition(kind string, text string) string
+	// Returns the site configuration files, rendered with siteData
+	siteFiles(opts Options) []siteFile
+}
+
+// A site configuration file of a site generator
+type siteFile struct {
+	// Path to the output file
+	path string
+	// Template association (without the template extension)
+	template string
+	// Merges the rendered file into the existing file, keeping the parts
+	// the user owns. nil if the rendered file replaces the existing file.
+	merge func(existing []byte, generated []byte) []byte
+}
+
+// Site generators by profile name
+var siteGenerators = map[string]siteGenerator{
+	ProfileMkdocs:     mkdocsSite{},
+	ProfileRegistry:   registrySite{},
+	ProfileHugo:       hugoSite{},
+	ProfileDocusaurus: docusaurusSite{},
+	ProfileBare:       bareSite{},
+}
+
+// -----------------------------------------------------------------------------
+// Site Generator Utility Functions
+// -----------------------------------------------------------------------------
+
+// lookupSiteGenerator returns the site generator of the profile with the
+// supplied name, or an error if there is no such profile
+func lookupSiteGenerator(name string) (siteGenerator, error) {
+	site, ok := siteGenerators[name]
+	if !ok {
+		names := []string{}
+		for profile := range siteGenerators {
+			names = append(names, profile)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf(
+			"Unrecognized profile [%s]. Expected one of [%s].",
+			name,
+			strings.Join(names, ", "),
+		)
+	}
+	return site, nil
+}
+
+// sitePagePath returns the path of the page documenting the provider,
+// resource, or data source on the output filesystem
+func sitePagePath(opts Options, site siteGenerator, schemaType int, name string) string {
+	return filepath.Join(opts.DocsDir, filepath.FromSlash(site.pagePath(schemaType, name)))
+}
+
+// newSiteData returns the template data of the site configuration files
+func newSiteData(provider *schema.Provider, opts Options, site siteGenerator) siteData {
+	data := siteData{
+		ProviderName:    opts.ProviderName,
+		DocsDir:         displayPath(opts, opts.DocsDir),
+		ProviderPage:    newSitePage(site, typeProvider, opts.ProviderName, nil),
+		Resources:       sortedNames(provider.ResourcesMap),
+		DataSources:     sortedNames(provider.DataSourcesMap),
+		ResourcePages:   []sitePage{},
+		DataSourcePages: []sitePage{},
+	}
+	for _, name := range data.Resources {
+		data.ResourcePages = append(data.ResourcePages, newSitePage(site, typeResource, name, provider.ResourcesMap[name]))
+	}
+	for _, name := range data.DataSources {
+		data.DataSourcePages = append(data.DataSourcePages, newSitePage(site, typeDataSource, name, provider.DataSourcesMap[name]))
+	}
+	data.Categories, data.Categorized = siteCategories(data.ResourcePages, data.DataSourcePages)
+	return data
+}
+
+// newSitePage returns the page of the provider, resource, or data source.
+// resource is nil for the provider.
+func newSitePage(site siteGenerator, schemaType int, name string, resource *schema.Resource) sitePage {
+	pagePath := site.pagePath(schemaType, name)
+	page := sitePage{
+		Name: name,
+		Path: pagePath,
+		ID:   strings.TrimSuffix(pagePath, path.Ext(pagePath)),
+	}
+	if resource != nil {
+		meta := parseMeta(schemaType, resource, resource.Schema)
+		page.Summary = meta.Summary
+		page.Category = meta.Category
+		if page.Category == "" {
+			page.Category = uncategorized
+		}
+	}
+	return page
+}
+
+// siteCategories groups the resource and data source pages by category.
+// Categories are sorted by name, with the "Uncategorized" category last.
+// Also returns whether or not any page has a category other than
+// "Uncategorized".
+func siteCategories(resourcePages []sitePage, dataSourcePages []sitePage) ([]siteCategory, bool) {
+	byName := map[string]*siteCategory{}
+	category := func(name string) *siteCategory {
+		if _, ok := byName[name]; !ok {
+			byName[name] = &siteCategory{
+				Name:        name,
+				Resources:   []sitePage{},
+				DataSources: []sitePage{},
+			}
+		}
+		return byName[name]
+	}
+	for _, page := range resourcePages {
+		c := category(page.Category)
+		c.Resources = append(c.Resources, page)
+	}
+	for _, page := range dataSourcePages {
+		c := category(page.Category)
+		c.DataSources = append(c.DataSources, page)
+	}
+
+	names := []string{}
+	for name := range byName {
+		if name != uncategorized {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	categorized := len(names) > 0
+	if _, ok := byName[uncategorized]; ok {
+		names = append(names, uncategorized)
+	}
+
+	categories := []siteCategory{}
+	for _, name := range names {
+		categories = append(categories, *byName[name])
+	}
+	return categories, categorized
+}
+
+// sortedNames returns the sorted names of the resources of a resource map
+func sortedNames(resources map[string]*schema.Resource) []string {
+	names := []string{}
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// defaultPagePath returns the page path of the layout shared by most site
+// generators: index.md, resources/NAME.md, datasources/NAME.md,
+// guides/NAME.md, and godoc/IMPORT_PATH.md
+func defaultPagePath(schemaType int, name string) string {
+	switch schemaType {
+	case typeGuide:
+		return guidePagePath(name)
+	case typeGoPackage:
+		return goPackagePagePath(name)
+	case typeResource:
+		return "resources/" + name + ".md"
+	case typeDataSource:
+		return "datasources/" + name + ".md"
+	}
+	return "index.md"
+}
+
+// guidePagePath returns the path of the page of a guide, which is the same
+// for every site generator
+func guidePagePath(name string) string {
+	return "guides/" + name + ".md"
+}
+
+// defaultPageTemplate returns the template association of the provider,
+// resource, or data source pages in the templates directory
+func defaultPageTemplate(schemaType int) string {
+	switch schemaType {
+	case typeResource:
+		return resourceMdTemplate
+	case typeDataSource:
+		return dataSourceMdTemplate
+	}
+	return providerMdTemplate
+}
+
+// yamlFrontMatter returns YAML front matter with the fields in order. Each
+// field is a key and a value; values are double quoted. Fields with an empty
+// value are left out.
+func yamlFrontMatter(fields ...[2]string) string {
+	b := strings.Builder{}
+	b.WriteString("---\n")
+	for _, field := range fields {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "%s: %q\n", field[0], field[1])
+		}
+	}
+	b.WriteString("---\n")
+	return b.String()
+}
+
+// githubAlert formats the text as a GitHub alert of the kind (ie: "> [!NOTE]").
+// Hugo renders the same syntax.
+func githubAlert(kind string, text string) string {
+	return "> [!" + strings.ToUpper(kind) + "]\n" + indentWith("> ", text)
+}
+
+// indentWith prefixes every line of the text with the prefix
+func indentWith(prefix string, text string) string {
+	return prefix + strings.Replace(text, "\n", "\n"+prefix, -1)
+}
+
+// -----------------------------------------------------------------------------
+// mkdocs
+// -----------------------------------------------------------------------------
+
+// mkdocs site: mkdocs.yml holds the navigation, and godoc.md is the index of
+// the Go API reference. Only the generated keys of an existing mkdocs.yml
+// are replaced. Pages have no front matter.
+type mkdocsSite struct{}
+
+func (mkdocsSite) pageTemplate(schemaType int) string {
+	return defaultPageTemplate(schemaType)
+}
+
+func (mkdocsSite) pagePath(schemaType int, name string) string {
+	return defaultPagePath(schemaType, name)
+}
+
+func (mkdocsSite) pageDirs() []string {
+	return []string{"resources", "datasources"}
+}
+
+func (mkdocsSite) frontMatter(data schemaDocData) string {
+	return ""
+}
+
+func (mkdocsSite) admonition(kind string, text string) string {
+	return "!!! " + kind + "\n" + indent(4, text)
+}
+
+func (mkdocsSite) siteFiles(opts Options) []siteFile {
+	return []siteFile{
+		{path: filepath.Join(opts.RootDir, "mkdocs.yml"), template: mkdocsYmlTemplate, merge: mergeMkdocsYml},
+		{path: filepath.Join(opts.DocsDir, "godoc.md"), template: godocMdTemplate},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Hugo
+// -----------------------------------------------------------------------------
+
+// Hugo site: the docs directory is the content directory, the provider page
+// is its section page (_index.md), and hugo.toml holds the main menu. Pages
+// have YAML front matter.
+type hugoSite struct{}
+
+func (hugoSite) pageTemplate(schemaType int) string {
+	return defaultPageTemplate(schemaType)
+}
+
+func (hugoSite) pagePath(schemaType int, name string) string {
+	if schemaType == typeProvider {
+		return "_index.md"
+	}
+	return defaultPagePath(schemaType, name)
+}
+
+func (hugoSite) pageDirs() []string {
+	return []string{"resources", "datasources"}
+}
+
+func (hugoSite) frontMatter(data schemaDocData) string {
+	return yamlFrontMatter(
+		[2]string{"title", data.Name},
+		[2]string{"description", data.Meta.Summary},
+	)
+}
+
+func (hugoSite) admonition(kind string, text string) string {
+	return githubAlert(kind, text)
+}
+
+func (hugoSite) siteFiles(opts Options) []siteFile {
+	return []siteFile{
+		{path: filepath.Join(opts.RootDir, "hugo.toml"), template: hugoTomlTemplate},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Docusaurus
+// -----------------------------------------------------------------------------
+
+// Docusaurus site: sidebars.js holds the navigation, and the provider page is
+// served at the root of the docs. Pages have YAML front matter.
+type docusaurusSite struct{}
+
+func (docusaurusSite) pageTemplate(schemaType int) string {
+	return defaultPageTemplate(schemaType)
+}
+
+func (docusaurusSite) pagePath(schemaType int, name string) string {
+	return defaultPagePath(schemaType, name)
+}
+
+func (docusaurusSite) pageDirs() []string {
+	return []string{"resources", "datasources"}
+}
+
+func (docusaurusSite) frontMatter(data schemaDocData) string {
+	slug := ""
+	if data.SchemaType == typeProvider {
+		slug = "/"
+	}
+	return yamlFrontMatter(
+		[2]string{"title", data.Name},
+		[2]string{"sidebar_label", data.Name},
+		[2]string{"description", data.Meta.Summary},
+		[2]string{"slug", slug},
+	)
+}
+
+func (docusaurusSite) admonition(kind string, text string) string {
+	// Docusaurus calls warnings "caution"
+	if kind == "warning" {
+		kind = "caution"
+	}
+	return ":::" + kind + "\n\n" + text + "\n\n:::"
+}
+
+func (docusaurusSite) siteFiles(opts Options) []siteFile {
+	return []siteFile{
+		{path: filepath.Join(opts.RootDir, "sidebars.js"), template: sidebarsJsTemplate},
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Bare Markdown
+// -----------------------------------------------------------------------------
+
+// Bare Markdown: plain pages meant to be browsed as they are (ie: on GitHub).
+// The provider page is README.md and links to every resource and data source
+// page. There is no site configuration and no front matter.
+type bareSite struct{}
+
+func (bareSite) pageTemplate(schemaType int) string {
+	if schemaType == typeProvider {
+		return bareIndexMdTemplate
+	}
+	return defaultPageTemplate(schemaType)
+}
+
+func (bareSite) pagePath(schemaType int, name string) string {
+	if schemaType == typeProvider {
+		return "README.md"
+	}
+	return defaultPagePath(schemaType, name)
+}
+
+func (bareSite) pageDirs() []string {
+	return []string{"resources", "datasources"}
+}
+
+func (bareSite) frontMatter(data schemaDocData) string {
+	return ""
+}
+
+func (bareSite) admonition(kind string, text string) string {
+	return githubAlert(kind, text)
+}
+
+func (bareSite) siteFiles(opts Options) []siteFile {
+	return []siteFile{}
+}
+
+// -----------------------------------------------------------------------------
+// Built-in Site Templates
+// -----------------------------------------------------------------------------
+
+// Body of the built-in Hugo site configuration
+const hugoToml = `title = "{{.ProviderName}}"
+contentDir = "{{.DocsDir}}"
+
+[[menu.main]]
+  identifier = "home"
+  name = "Home"
+  pageRef = "/"
+  weight = 1
+{{- if .Guides}}
+
+[[menu.main]]
+  identifier = "guides"
+  name = "Guides"
+  weight = 2
+{{- range .Guides}}
+
+[[menu.main]]
+  parent = "guides"
+  name = {{printf "%q" .Name}}
+  pageRef = "/{{.ID}}"
+  weight = {{.Weight}}
+{{- end}}
+{{- end}}
+{{- if .ResourcePages}}
+
+[[menu.main]]
+  identifier = "resources"
+  name = "Resources"
+  weight = 3
+{{- if .Categorized}}
+{{- range .Categories}}
+{{- if .Resources}}
+{{- $category := printf "resources-%s" (slug .Name)}}
+
+[[menu.main]]
+  identifier = "{{$category}}"
+  parent = "resources"
+  name = {{printf "%q" .Name}}
+{{- range .Resources}}
+
+[[menu.main]]
+  parent = "{{$category}}"
+  name = "{{.Name}}"
+  pageRef = "/{{.ID}}"
+{{- end}}
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .ResourcePages}}
+
+[[menu.main]]
+  parent = "resources"
+  name = "{{.Name}}"
+  pageRef = "/{{.ID}}"
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .DataSourcePages}}
+
+[[menu.main]]
+  identifier = "datasources"
+  name = "Data Sources"
+  weight = 4
+{{- if .Categorized}}
+{{- range .Categories}}
+{{- if .DataSources}}
+{{- $category := printf "datasources-%s" (slug .Name)}}
+
+[[menu.main]]
+  identifier = "{{$category}}"
+  parent = "datasources"
+  name = {{printf "%q" .Name}}
+{{- range .DataSources}}
+
+[[menu.main]]
+  parent = "{{$category}}"
+  name = "{{.Name}}"
+  pageRef = "/{{.ID}}"
+{{- end}}
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .DataSourcePages}}
+
+[[menu.main]]
+  parent = "datasources"
+  name = "{{.Name}}"
+  pageRef = "/{{.ID}}"
+{{- end}}
+{{- end}}
+{{- end}}
+{{- if .GoPackages}}
+
+[[menu.main]]
+  identifier = "goapi"
+  name = "Go API"
+  weight = 5
+{{- range .GoPackages}}
+
+[[menu.main]]
+  parent = "goapi"
+  name = "{{.Name}}"
+  pageRef = "/{{.ID}}"
+{{- end}}
+{{- end}}
+`
+
+// Body of the built-in Docusaurus sidebar
+const sidebarsJs = `module.exports = {
+  docs: [
+    '{{.ProviderPage.ID}}',
+{{- if .Guides}}
+    {
+      type: 'category',
+      label: 'Guides',
+      items: [
+{{- range .Guides}}
+        '{{.ID}}',
+{{- end}}
+      ],
+    },
+{{- end}}
+{{- if .ResourcePages}}
+    {
+      type: 'category',
+      label: 'Resources',
+      items: [
+{{- if .Categorized}}
+{{- range .Categories}}
+{{- if .Resources}}
+        {
+          type: 'category',
+          label: {{printf "%q" .Name}},
+          items: [
+{{- range .Resources}}
+            '{{.ID}}',
+{{- end}}
+          ],
+        },
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .ResourcePages}}
+        '{{.ID}}',
+{{- end}}
+{{- end}}
+      ],
+    },
+{{- end}}
+{{- if .DataSourcePages}}
+    {
+      type: 'category',
+      label: 'Data Sources',
+      items: [
+{{- if .Categorized}}
+{{- range .Categories}}
+{{- if .DataSources}}
+        {
+          type: 'category',
+          label: {{printf "%q" .Name}},
+          items: [
+{{- range .DataSources}}
+            '{{.ID}}',
+{{- end}}
+          ],
+        },
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .DataSourcePages}}
+        '{{.ID}}',
+{{- end}}
+{{- end}}
+      ],
+    },
+{{- end}}
+{{- if .GoPackages}}
+    {
+      type: 'category',
+      label: 'Go API',
+      items: [
+{{- range .GoPackages}}
+        '{{.ID}}',
+{{- end}}
+      ],
+    },
+{{- end}}
+  ],
+};
+`
+
+// Body of the built-in bare Markdown provider page: the provider page from
+// the templates directory, followed by links to every guide and page, with
+// the resources and data sources grouped by category if any page has one,
+// and to the Go API reference
+const bareIndexMd = `{{template "index.md.template" .}}
+{{- if .Guides}}
+## Guides
+
+{{range .Guides}}* [{{.Name}}]({{.Path}}){{if .Summary}} - {{.Summary}}{{end}}
+{{end}}{{end}}
+{{- if .Resources}}
+## Resources
+{{range .Categories}}{{if .Resources}}{{if $.Categorized}}
+### {{.Name}}
+{{end}}
+{{range .Resources}}* {{resourceLink $ .Name}}{{if .Summary}} - {{.Summary}}{{end}}
+{{end}}{{end}}{{end}}{{end}}
+{{- if .DataSources}}
+## Data Sources
+{{range .Categories}}{{if .DataSources}}{{if $.Categorized}}
+### {{.Name}}
+{{end}}
+{{range .DataSources}}* {{dataSourceLink $ .Name}}{{if .Summary}} - {{.Summary}}{{end}}
+{{end}}{{end}}{{end}}{{end}}
+{{- if .GoPackages}}
+## Go API
+
+{{range .GoPackages}}* [` + "`{{.Name}}`" + `]({{.Path}}){{if .Summary}} - {{.Summary}}{{end}}
+{{end}}{{end}}`
@@ -0,0 +1,360 @@
+package autodoc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// -----------------------------------------------------------------------------
+// Site Generators
+// -----------------------------------------------------------------------------
+
+// documentSite generates the documentation of providerFoo with the profile
+// and built-in templates, and returns the output filesystem
+func documentSite(t *testing.T, profile string) *MemoryFileSystem {
+	fs := NewMemoryFileSystem()
+	errs := DocumentWithOptions(providerFoo(), Options{
+		ProviderName: "terraform-provider-foo",
+		RootDir:      "/out",
+		DocsDir:      "/out/docs",
+		TemplatesDir: "/does/not/exist",
+		Profile:      profile,
+		FileSystem:   fs,
+	})
+	if len(errs) != 0 {
+		t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+	}
+	return fs
+}
+
+// expectSiteFiles fails the test if the filesystem does not contain exactly
+// the files, or a file does not contain its expected snippets
+func expectSiteFiles(t *testing.T, fs *MemoryFileSystem, files []string, snippets map[string][]string) {
+	actualFiles := fs.Files()
+	if strings.Join(files, ",") != strings.Join(actualFiles, ",") {
+		t.Fatalf(
+			"DocumentWithOptions did not write the correct files. Expected "+
+				"%v, got %v.",
+			files,
+			actualFiles,
+		)
+	}
+	for path, expected := range snippets {
+		content, _ := fs.ReadFile(path)
+		for _, snippet := range expected {
+			if !strings.Contains(string(content), snippet) {
+				t.Fatalf(
+					"DocumentWithOptions did not return the correct output. "+
+						"Expected [%s] in [%s]:\n%s",
+					snippet,
+					path,
+					string(content),
+				)
+			}
+		}
+	}
+}
+
+// Ensures the hugo profile generates hugo.toml, the section page of the
+// content directory, and pages with front matter
+func TestDocumentWithOptions_Hugo(t *testing.T) {
+	fs := documentSite(t, ProfileHugo)
+	expectSiteFiles(t, fs, []string{
+		"/out/.autodoc-manifest",
+		"/out/docs/_index.md",
+		"/out/docs/datasources/foo_baz.md",
+		"/out/docs/resources/foo_bar.md",
+		"/out/hugo.toml",
+	}, map[string][]string{
+		"/out/hugo.toml": {
+			"title = \"terraform-provider-foo\"\ncontentDir = \"docs\"\n",
+			"  parent = \"resources\"\n  name = \"foo_bar\"\n  pageRef = \"/resources/foo_bar\"\n",
+			"  parent = \"datasources\"\n  name = \"foo_baz\"\n  pageRef = \"/datasources/foo_baz\"\n",
+		},
+		"/out/docs/_index.md": {
+			"---\ntitle: \"terraform-provider-foo\"\n---\n# terraform-provider-foo\n",
+		},
+		"/out/docs/resources/foo_bar.md": {
+			"---\ntitle: \"foo_bar\"\n---\n# foo_bar\n",
+			"> [!NOTE]\n> This resource cannot be created. It must be imported.\n",
+		},
+	})
+}
+
+// Ensures the docusaurus profile generates sidebars.js and pages with front
+// matter, with the provider page at the root of the docs
+func TestDocumentWithOptions_Docusaurus(t *testing.T) {
+	fs := documentSite(t, ProfileDocusaurus)
+	expectSiteFiles(t, fs, []string{
+		"/out/.autodoc-manifest",
+		"/out/docs/datasources/foo_baz.md",
+		"/out/docs/index.md",
+		"/out/docs/resources/foo_bar.md",
+		"/out/sidebars.js",
+	}, map[string][]string{
+		"/out/sidebars.js": {
+			"  docs: [\n    'index',\n",
+			"      label: 'Resources',\n      items: [\n        'resources/foo_bar',\n      ],\n",
+			"      label: 'Data Sources',\n      items: [\n        'datasources/foo_baz',\n      ],\n",
+		},
+		"/out/docs/index.md": {
+			"---\ntitle: \"terraform-provider-foo\"\nsidebar_label: \"terraform-provider-foo\"\nslug: \"/\"\n---\n",
+		},
+		"/out/docs/resources/foo_bar.md": {
+			"---\ntitle: \"foo_bar\"\nsidebar_label: \"foo_bar\"\n---\n# foo_bar\n",
+			":::note\n\nThis resource cannot be updated. Any change re-creates it.\n\n:::\n",
+		},
+	})
+}
+
+// Ensures the bare profile generates plain pages, with a README.md that
+// links to every page
+func TestDocumentWithOptions_Bare(t *testing.T) {
+	fs := documentSite(t, ProfileBare)
+	expectSiteFiles(t, fs, []string{
+		"/out/.autodoc-manifest",
+		"/out/docs/README.md",
+		"/out/docs/datasources/foo_baz.md",
+		"/out/docs/resources/foo_bar.md",
+	}, map[string][]string{
+		"/out/docs/README.md": {
+			"# terraform-provider-foo\n",
+			"## Resources\n\n* [`foo_bar`](resources/foo_bar.md)\n",
+			"## Data Sources\n\n* [`foo_baz`](datasources/foo_baz.md)\n",
+		},
+	})
+
+	resourceDoc, _ := fs.ReadFile("/out/docs/resources/foo_bar.md")
+	if !strings.HasPrefix(string(resourceDoc), "# foo_bar\n") {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct output. "+
+				"Expected the page to start with its title:\n%s",
+			string(resourceDoc),
+		)
+	}
+}
+
+// Ensures an unknown profile is reported with the known profiles
+func TestDocumentWithOptions_UnknownProfile(t *testing.T) {
+	errs := DocumentWithOptions(providerFoo(), Options{
+		RootDir:    "/out",
+		Profile:    "jekyll",
+		FileSystem: NewMemoryFileSystem(),
+	})
+	expected := "Unrecognized profile [jekyll]. Expected one of [bare, " +
+		"docusaurus, hugo, mkdocs, registry]."
+	if len(errs) != 1 || errs[0].Error() != expected {
+		t.Fatalf(
+			"DocumentWithOptions did not return the correct errors. Expected "+
+				"[%s], got %v.",
+			expected,
+			errs,
+		)
+	}
+}
+
+// Ensures each site generator formats admonitions in its Markdown dialect
+func TestSiteGenerator_Admonition(t *testing.T) {
+	cases := []struct {
+		profile  string
+		kind     string
+		expected string
+	}{
+		{ProfileMkdocs, "note", "!!! note\n    line 1\n    line 2"},
+		{ProfileRegistry, "warning", "~> line 1\nline 2"},
+		{ProfileHugo, "note", "> [!NOTE]\n> line 1\n> line 2"},
+		{ProfileDocusaurus, "warning", ":::caution\n\nline 1\nline 2\n\n:::"},
+		{ProfileBare, "warning", "> [!WARNING]\n> line 1\n> line 2"},
+	}
+	for _, c := range cases {
+		site, _ := lookupSiteGenerator(c.profile)
+		if actual := site.admonition(c.kind, "line 1\nline 2"); actual != c.expected {
+			t.Fatalf(
+				"admonition did not return the correct output for [%s]. "+
+					"Expected [%s], got [%s].",
+				c.profile,
+				c.expected,
+				actual,
+			)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Categories
+// -----------------------------------------------------------------------------
+
+// providerCategories returns a provider with categorized and uncategorized
+// resources and data sources
+func providerCategories() *schema.Provider {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Description = "A bar. @CATEGORY Networking"
+	provider.ResourcesMap["foo_qux"] = resourceFoo()
+	provider.ResourcesMap["foo_qux"].Description = "A qux."
+	provider.ResourcesMap["foo_zap"] = resourceFoo()
+	provider.ResourcesMap["foo_zap"].Description = "@CATEGORY Compute"
+	return provider
+}
+
+// Ensures resources and data sources are grouped by their category, with the
+// uncategorized ones last
+func TestSiteCategories(t *testing.T) {
+	data := newSiteData(providerCategories(), Options{DocsDir: "docs"}, mkdocsSite{})
+	if !data.Categorized {
+		t.Fatalf("newSiteData did not return the correct output. Expected categorized pages.")
+	}
+
+	actual := []string{}
+	for _, category := range data.Categories {
+		names := []string{}
+		for _, page := range category.Resources {
+			names = append(names, page.Name+"="+page.Summary)
+		}
+		for _, page := range category.DataSources {
+			names = append(names, "data."+page.Name)
+		}
+		actual = append(actual, category.Name+": "+strings.Join(names, ", "))
+	}
+	expected := []string{
+		"Compute: foo_zap=",
+		"Networking: foo_bar=A bar.",
+		"Uncategorized: foo_qux=A qux., data.foo_baz",
+	}
+	if strings.Join(expected, "\n") != strings.Join(actual, "\n") {
+		t.Fatalf(
+			"newSiteData did not return the correct categories. Expected "+
+				"%v, got %v.",
+			expected,
+			actual,
+		)
+	}
+
+	data = newSiteData(providerFoo(), Options{DocsDir: "docs"}, mkdocsSite{})
+	if data.Categorized || len(data.Categories) != 1 || data.Categories[0].Name != "Uncategorized" {
+		t.Fatalf(
+			"newSiteData did not return the correct categories. Expected "+
+				"only [Uncategorized], got %+v.",
+			data.Categories,
+		)
+	}
+}
+
+// Ensures the built-in site templates group the navigation by category
+func TestDocumentWithOptions_Categories(t *testing.T) {
+	for _, c := range []struct {
+		profile  string
+		path     string
+		expected string
+	}{
+		{
+			ProfileMkdocs,
+			"/out/mkdocs.yml",
+			"  - Resources:\n" +
+				"    - \"Compute\":\n      - foo_zap: 'resources/foo_zap.md'\n" +
+				"    - \"Networking\":\n      - foo_bar: 'resources/foo_bar.md'\n" +
+				"    - \"Uncategorized\":\n      - foo_qux: 'resources/foo_qux.md'\n" +
+				"  - Data Sources:\n" +
+				"    - \"Uncategorized\":\n      - foo_baz: 'datasources/foo_baz.md'\n",
+		},
+		{
+			ProfileDocusaurus,
+			"/out/sidebars.js",
+			"          label: \"Networking\",\n          items: [\n            'resources/foo_bar',\n          ],\n",
+		},
+		{
+			ProfileHugo,
+			"/out/hugo.toml",
+			"  identifier = \"resources-networking\"\n  parent = \"resources\"\n  name = \"Networking\"\n\n" +
+				"[[menu.main]]\n  parent = \"resources-networking\"\n  name = \"foo_bar\"\n",
+		},
+		{
+			ProfileBare,
+			"/out/docs/README.md",
+			"## Resources\n\n### Compute\n\n* [`foo_zap`](resources/foo_zap.md)\n\n" +
+				"### Networking\n\n* [`foo_bar`](resources/foo_bar.md) - A bar.\n\n" +
+				"### Uncategorized\n\n* [`foo_qux`](resources/foo_qux.md) - A qux.\n",
+		},
+		{
+			ProfileRegistry,
+			"/out/docs/resources/bar.md",
+			"subcategory: \"Networking\"\n",
+		},
+	} {
+		fs := NewMemoryFileSystem()
+		errs := DocumentWithOptions(providerCategories(), Options{
+			ProviderName: "terraform-provider-foo",
+			RootDir:      "/out",
+			DocsDir:      "/out/docs",
+			TemplatesDir: "/does/not/exist",
+			Profile:      c.profile,
+			FileSystem:   fs,
+		})
+		if len(errs) != 0 {
+			t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+		}
+		content, _ := fs.ReadFile(c.path)
+		if !strings.Contains(string(content), c.expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Expected [%s] in [%s]:\n%s",
+				c.expected,
+				c.path,
+				string(content),
+			)
+		}
+	}
+}
+
+// Ensures category names with characters that are special in YAML, TOML, or
+// JavaScript are quoted in the built-in navigation
+func TestDocumentWithOptions_CategoryQuoting(t *testing.T) {
+	provider := providerFoo()
+	provider.ResourcesMap["foo_bar"].Description = "A bar. @CATEGORY Networking: Partner's APIs"
+	for _, c := range []struct {
+		profile  string
+		path     string
+		expected string
+	}{
+		{
+			ProfileMkdocs,
+			"/out/mkdocs.yml",
+			"    - \"Networking: Partner's APIs\":\n      - foo_bar: 'resources/foo_bar.md'\n",
+		},
+		{
+			ProfileDocusaurus,
+			"/out/sidebars.js",
+			"          label: \"Networking: Partner's APIs\",\n",
+		},
+		{
+			ProfileHugo,
+			"/out/hugo.toml",
+			"  identifier = \"resources-networking-partners-apis\"\n" +
+				"  parent = \"resources\"\n  name = \"Networking: Partner's APIs\"\n",
+		},
+	} {
+		fs := NewMemoryFileSystem()
+		errs := DocumentWithOptions(provider, Options{
+			ProviderName: "terraform-provider-foo",
+			RootDir:      "/out",
+			DocsDir:      "/out/docs",
+			TemplatesDir: "/does/not/exist",
+			Profile:      c.profile,
+			FileSystem:   fs,
+		})
+		if len(errs) != 0 {
+			t.Fatalf("DocumentWithOptions returned errors: %v", errs)
+		}
+		content, _ := fs.ReadFile(c.path)
+		if !strings.Contains(string(content), c.expected) {
+			t.Fatalf(
+				"DocumentWithOptions did not return the correct output. "+
+					"Expected [%s] in [%s]:\n%s",
+				c.expected,
+				c.path,
+				string(content),
+			)
+		}
+	}
+}
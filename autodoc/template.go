@@ -1,9 +1,14 @@
 package autodoc
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"text/template"
+	"text/template/parse"
 )
 
 // NOTE(ALL): If you make modifications to the template associations, be
@@ -19,14 +24,50 @@ import (
 const (
 	// Template file for mkdocs.yml
 	mkdocsYmlTemplate = "mkdocs.yml"
-	// Template file for godoc.md
+	// Template file for godoc.md, the index of the Go API reference
 	godocMdTemplate = "godoc.md"
+	// Template file for the Go API reference page of each Go package
+	goPackageMdTemplate = "godoc-package.md"
 	// Template file for all provider resources
 	resourceMdTemplate = "resource.md"
 	// Template file for all provider data sources
 	dataSourceMdTemplate = "datasource.md"
 	// Template file for the provider itself
 	providerMdTemplate = "index.md"
+	// Built-in partial template that renders a single argument as a list
+	// item, including its flags and constraints. It is used by the built-in
+	// templates and can be over-ridden by a user template of the same name.
+	argumentPartialTemplate = "_argument"
+	// Built-in partial template that renders the nested block sections of a
+	// provider, resource, or data source. It can be included from any
+	// template with {{template "_blocks.template" .}} (using the configured
+	// template extension) and can be overridden by a user template of the
+	// same name.
+	blocksPartialTemplate = "_blocks"
+	// Built-in partial template that renders the argument list of a
+	// provider, resource, or data source with {{template
+	// "_argument.template" .}}, or a sentence saying it has no arguments
+	argumentsPartialTemplate = "_arguments"
+	// Built-in partial template that renders the example HCL configuration
+	// of a provider, resource, or data source as a fenced code block
+	examplePartialTemplate = "_example"
+	// Prefix of the names of partial templates. Partials are included from
+	// other templates and are never rendered to a file of their own.
+	partialTemplatePrefix = "_"
+)
+
+// Sub directories of the templates directory with the templates of single
+// resources and data sources (ie: resources/foo_bar.md.template). A page is
+// rendered with its own template if it exists, and with the template of its
+// schema type otherwise.
+const (
+	// Sub directory of the resource templates
+	resourceTemplatesDir = "resources"
+	// Sub directory of the data source templates
+	dataSourceTemplatesDir = "datasources"
+	// Sub directory of the guide templates. Each NAME.md template in it is
+	// rendered to the guide page guides/NAME.md.
+	guidesTemplatesDir = "guides"
 )
 
 // The type of schema that is being documented
@@ -37,20 +78,79 @@ const (
 	typeResource
 	// Data source schema map
 	typeDataSource
+	// Hand-written guide page, rendered from the guides templates directory
+	typeGuide
+	// Go API reference page of a Go package
+	typeGoPackage
 )
 
 // -----------------------------------------------------------------------------
 // Template Data Structs
 // -----------------------------------------------------------------------------
 
-// Template data needed to generate mkdocs.yml
-type mkdocsYmlData struct {
-	// The docs_dir - location where documentation files are generated to
+// Template data needed to generate the site configuration files (ie:
+// mkdocs.yml)
+type siteData struct {
+	// Name of the provider being documented
+	ProviderName string
+	// The docs_dir - location where documentation files are generated to.
+	// This is relative to the root directory (where mkdocs.yml is generated)
+	// if the docs directory is under it.
 	DocsDir string
+	// Page of the provider
+	ProviderPage sitePage
 	// List of provider resources
 	Resources []string
 	// List of provider data sources
 	DataSources []string
+	// Pages of the provider resources, in the same order as Resources
+	ResourcePages []sitePage
+	// Pages of the provider data sources, in the same order as DataSources
+	DataSourcePages []sitePage
+	// Resources and data sources grouped by their @CATEGORY tag, sorted by
+	// category name. The "Uncategorized" category is last.
+	Categories []siteCategory
+	// Whether or not any resource or data source has a category. If not,
+	// all of them are in the "Uncategorized" category.
+	Categorized bool
+	// Pages of the guides, sorted by weight and then by title
+	Guides []sitePage
+	// Pages of the Go API reference, one per Go package, sorted by import
+	// path
+	GoPackages []sitePage
+	// Path of the changelog page relative to the docs directory. Empty if
+	// there is no changelog page in the docs directory.
+	Changelog string
+}
+
+// A generated page, as it is referenced from the site navigation
+type sitePage struct {
+	// Name of the provider, resource, or data source
+	Name string
+	// Path of the page relative to the docs directory (ie:
+	// "resources/foo_bar.md")
+	Path string
+	// Path of the page without its extension (ie: "resources/foo_bar").
+	// Docusaurus identifies documents by it.
+	ID string
+	// Summary of the resource or data source
+	Summary string
+	// Category of the resource or data source, from the @CATEGORY tag.
+	// "Uncategorized" if it has none.
+	Category string
+	// Weight of a guide from its front matter. Guides are ordered by weight
+	// in the navigation. 0 for other pages.
+	Weight int
+}
+
+// A named group of resources and data sources
+type siteCategory struct {
+	// Name of the category
+	Name string
+	// Pages of the resources in the category, sorted by name
+	Resources []sitePage
+	// Pages of the data sources in the category, sorted by name
+	DataSources []sitePage
 }
 
 // Template data needed to generate a provider, resource, or data source
@@ -67,12 +167,186 @@ type schemaDocData struct {
 	SchemaType int
 	// Name of the resource
 	Name string
+	// Name of the resource without its provider prefix (ie: "foo_bar" =>
+	// "bar"). For the provider, this is the same as Name.
+	ShortName string
+	// Name of the provider being documented
+	ProviderName string
+	// Type name of the provider in the config (ie: "foo" for a provider with
+	// the resource "foo_bar"), inferred from its resource names
+	ProviderType string
+	// Front matter of the page, including the trailing newline. Empty if the
+	// output profile does not use front matter.
+	FrontMatter string
+	// Names of the provider resources and data sources, sorted. Only set for
+	// the provider.
+	Resources   []string
+	DataSources []string
+	// Resources and data sources grouped by category, and whether or not any
+	// of them has a category, as in the site configuration data. Only set for
+	// the provider.
+	Categories  []siteCategory
+	Categorized bool
+	// Pages of the guides, sorted by weight and then by title. Only set for
+	// the provider.
+	Guides []sitePage
+	// Pages of the Go API reference, sorted by import path. Only set for the
+	// provider.
+	GoPackages []sitePage
+	// Whether or not the resource can be imported
+	Importable bool
+	// Format of the ID used to import the resource, from the @IMPORT_ID tag.
+	// Empty if the resource is imported by its ID.
+	ImportIDFormat string
+	// List of the operation timeouts that can be configured in the
+	// resource's timeouts block
+	Timeouts []schemaTimeout
+	// Complete, syntactically valid HCL configuration block for the resource
+	// with its arguments set to their @EXAMPLE values, including the trailing
+	// newline
+	ExampleHCL string
 	// Metadata information about the resource
 	Meta meta
 	// List of resource's exported schema attributes
 	Attributes []schemaAttribute
 	// List of resource's schema arguments
 	Arguments []schemaArgument
+	// Tree of the resource's nested configuration blocks
+	Blocks []schemaBlock
+	// The provider, resources, and data sources whose descriptions reference
+	// this resource or data source with @REF, sorted by name
+	ReferencedBy []schemaReference
+}
+
+// Template data needed to render a guide page. Guides have access to the
+// provider model: its arguments and blocks, the schema of every resource and
+// data source, and the pages of every resource, data source, and guide.
+type guideDocData struct {
+	// Name of the guide, its template file name without the extensions (ie:
+	// "authentication")
+	Name string
+	// Title of the guide from its front matter. Defaults to the name.
+	Title string
+	// Description of the guide from its front matter
+	Description string
+	// Weight of the guide from its front matter
+	Weight int
+	// Path of the guide page relative to the docs directory (ie:
+	// "guides/authentication.md")
+	Path string
+	// Name of the provider being documented
+	ProviderName string
+	// Type name of the provider in the config
+	ProviderType string
+	// Front matter of the page, including the trailing newline. Empty if the
+	// output profile does not use front matter.
+	FrontMatter string
+	// The provider's schema arguments, sorted by name
+	Arguments []schemaArgument
+	// Tree of the provider's nested configuration blocks
+	Blocks []schemaBlock
+	// Template data of each resource and data source, as on its own page,
+	// keyed by name (ie: (index .Resources "foo_bar").Arguments). References
+	// in the descriptions are linked relative to the guide.
+	Resources   map[string]schemaDocData
+	DataSources map[string]schemaDocData
+	// Pages of the resources, data sources, and guides, and the resources
+	// and data sources grouped by category, as in the site configuration
+	// data
+	ResourcePages   []sitePage
+	DataSourcePages []sitePage
+	Guides          []sitePage
+	Categories      []siteCategory
+	Categorized     bool
+}
+
+// Template data needed to render the Go API reference page of a Go package.
+// The exported identifiers are in the order of go/doc: sorted by name, with
+// the constants, variables, and constructor functions of a type listed with
+// the type.
+type goPackageDocData struct {
+	// Name of the package (ie: "autodoc")
+	Name string
+	// Import path of the package
+	ImportPath string
+	// First sentence of the package documentation
+	Synopsis string
+	// Package documentation, as Markdown
+	Doc string
+	// Path of the page relative to the docs directory (ie:
+	// "godoc/github.com/foo/bar.md")
+	Path string
+	// Name of the provider being documented
+	ProviderName string
+	// Front matter of the page, including the trailing newline. Empty if the
+	// output profile does not use front matter.
+	FrontMatter string
+	// Exported constant and variable declarations that are not associated
+	// with a type
+	Constants []goDeclDoc
+	Variables []goDeclDoc
+	// Exported functions that are not constructors of a type
+	Functions []goDeclDoc
+	// Exported types
+	Types []goTypeDoc
+	// Pages of the Go API reference, as in the site configuration data
+	GoPackages []sitePage
+}
+
+// Template data representing an exported declaration of a Go package
+type goDeclDoc struct {
+	// Name of the function or method, or the comma separated names declared
+	// by a constant or variable declaration
+	Name string
+	// Receiver type of a method (ie: "*Options"). Empty for other
+	// declarations.
+	Recv string
+	// HTML anchor of the declaration (ie: "Document" or "Options.Validate")
+	Anchor string
+	// Go source of the declaration, without its doc comment or body
+	Decl string
+	// Documentation of the declaration, as Markdown
+	Doc string
+}
+
+// Template data representing an exported type of a Go package
+type goTypeDoc struct {
+	// Name of the type
+	Name string
+	// HTML anchor of the type
+	Anchor string
+	// Go source of the type declaration, without its doc comment and
+	// unexported fields
+	Decl string
+	// Documentation of the type, as Markdown
+	Doc string
+	// Constants and variables of the type
+	Constants []goDeclDoc
+	Variables []goDeclDoc
+	// Functions returning the type (ie: NewOptions)
+	Functions []goDeclDoc
+	// Methods of the type
+	Methods []goDeclDoc
+}
+
+// Template data representing a page that references the page being rendered
+type schemaReference struct {
+	// Name of the provider, resource, or data source
+	Name string
+	// The type of schema. This should be one of the typeXxx constants.
+	SchemaType int
+	// Path of its page, relative to the page being rendered
+	Path string
+}
+
+// Template data representing a configurable operation timeout of a resource
+type schemaTimeout struct {
+	// Name of the timeout in the timeouts block (ie: "create")
+	Name string
+	// Default duration of the timeout (ie: "20m")
+	Default string
+	// Description of the operation the timeout applies to
+	Description string
 }
 
 // Template data representing an attribute of a resource
@@ -83,6 +357,20 @@ type schemaAttribute struct {
 	Type string
 	// Description of the attribute
 	Description string
+	// Whether or not the attribute's value is sensitive and hidden from the
+	// plan output
+	Sensitive bool
+	// Deprecation message of the attribute. Empty if it is not deprecated.
+	Deprecated string
+	// Anchor of the section documenting this attribute's nested block. Empty
+	// if the attribute is not a nested block.
+	Anchor string
+	// Anchor of the attribute's list item. References to the attribute
+	// (ie: "@REF(foo_bar.name)") link to it.
+	AttributeAnchor string
+	// Values of the custom tags of the attribute by tag name, without the
+	// '@'. Tags without a value are set to "true".
+	Custom map[string]string
 }
 
 // Template data representing an argument of a resource
@@ -105,6 +393,69 @@ type schemaArgument struct {
 	// or the list of arguments in the ConflictsWith definition can be set
 	// in the config.
 	ConflictsWith []string
+	// A list of schema arguments of which exactly one, including this
+	// argument, must be set in the config
+	ExactlyOneOf []string
+	// A list of schema arguments of which at least one, including this
+	// argument, must be set in the config
+	AtLeastOneOf []string
+	// A list of schema arguments that must also be set in the config when
+	// this argument is set
+	RequiredWith []string
+	// A list of schema arguments that cause this argument to be re-computed
+	// when they change
+	ComputedWhen []string
+	// The default value of the argument as an HCL literal (ie: "foo", 10,
+	// ["a", "b"]). Empty if the argument has no static default.
+	Default string
+	// Whether or not the argument's value is sensitive and hidden from the
+	// plan output
+	Sensitive bool
+	// Deprecation message of the argument. Empty if it is not deprecated.
+	Deprecated string
+	// Minimum number of items of a list or set. 0 denotes no minimum.
+	MinItems int
+	// Maximum number of items of a list or set. 0 denotes no maximum.
+	MaxItems int
+	// How the argument is represented in the config: "auto" (the default,
+	// determined by its type), "attribute", or "block".
+	ConfigMode string
+	// Anchor of the section documenting this argument's nested block. Empty
+	// if the argument is not a nested block.
+	Anchor string
+	// Values of the custom tags of the argument by tag name, without the
+	// '@'. Tags without a value are set to "true".
+	Custom map[string]string
+}
+
+// Template data representing a nested configuration block of a resource. A
+// nested block is any list or set schema whose Elem is a *schema.Resource.
+type schemaBlock struct {
+	// Name of the block
+	Name string
+	// Dot separated path to the block from the root of the resource
+	// (ie: "rule.condition")
+	Path string
+	// Anchor of the block's section in the generated page
+	Anchor string
+	// How the blocks are nested in the config. This is either "list" or "set".
+	NestingMode string
+	// Minimum number of blocks that must be supplied. 0 denotes no minimum.
+	MinItems int
+	// Maximum number of blocks that can be supplied. 0 denotes no maximum.
+	MaxItems int
+	// Whether or not the block is optional
+	Optional bool
+	// Whether or not the block is computed
+	Computed bool
+	// Description of the block
+	Description string
+	// List of the block's exported schema attributes
+	Attributes []schemaAttribute
+	// List of the block's schema arguments
+	Arguments []schemaArgument
+	// The blocks nested under this block
+	Blocks []schemaBlock
 }
 
 // -----------------------------------------------------------------------------
@@ -112,26 +463,182 @@ type schemaArgument struct {
 // -----------------------------------------------------------------------------
 
 // parseTemplates recursively searches the templates directory (from
-// parsedArgs.templatesDir) for template files (from
-// parsedArgs.templateFileExt). Returns the text template reference on
-// success or an error if one was encountered.
-func parseTemplates(args parsedArgs) (*template.Template, error) {
-	t := template.New("")
+// Options.TemplatesDir) for template files (from Options.TemplateExt).
+// Returns the text template reference on success or an error if one was
+// encountered.
+//
+// The built-in templates are loaded before the templates directory is walked
+// so user templates of the same name take precedence, one file at a time.
+// All templates have the built-in template functions and the functions from
+// Options.Funcs.
+func parseTemplates(opts Options) (*template.Template, error) {
+	t := template.New("").Funcs(templateFuncs(opts))
+
+	for name, body := range builtinTemplates {
+		if builtinErr := parseBuiltinTemplate(t, name, body, opts); builtinErr != nil {
+			return nil, builtinErr
+		}
+	}
+
+	// a missing templates directory is not an error; only the built-in
+	// templates are available
+	if _, statErr := os.Stat(opts.TemplatesDir); os.IsNotExist(statErr) {
+		return t, nil
+	}
 
 	// walk the templates directory, if we encounter any sub directories we load
 	// the template files in them and keep walking down
-	var parseErr error
-	walkErr := filepath.Walk(args.templatesDir, func(path string, info os.FileInfo, err error) error {
-		pathGlob := filepath.Join(path, "*"+args.templateFileExt)
-		if info.IsDir() {
-			_, parseErr = t.ParseGlob(pathGlob)
+	walkErr := filepath.Walk(opts.TemplatesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		// directories without template files are skipped
+		matches, globErr := filepath.Glob(filepath.Join(path, "*"+opts.TemplateExt))
+		if globErr != nil || len(matches) == 0 {
+			return globErr
+		}
+		// templates of single resources and data sources are named after
+		// their directory, so they do not collide with the other templates
+		rel, _ := filepath.Rel(opts.TemplatesDir, path)
+		if rel == guidesTemplatesDir {
+			return parseGuideTemplates(t, opts, matches)
+		}
+		if rel != resourceTemplatesDir && rel != dataSourceTemplatesDir {
+			_, parseErr := t.ParseFiles(matches...)
 			return parseErr
 		}
+		for _, match := range matches {
+			body, readErr := ioutil.ReadFile(match)
+			if readErr != nil {
+				return readErr
+			}
+			name := rel + "/" + filepath.Base(match)
+			if _, parseErr := t.New(name).Parse(string(body)); parseErr != nil {
+				return parseErr
+			}
+		}
 		return nil
 	})
 	if walkErr != nil {
 		return nil, walkErr
 	}
 
+	if includeErr := checkTemplateIncludes(t); includeErr != nil {
+		return nil, includeErr
+	}
 	return t, nil
 }
+
+// schemaTemplateName returns the name of the template a resource or data
+// source page is rendered with: its own template in the resource or data
+// source templates directory if it exists, or the page template of the site
+// generator otherwise
+func schemaTemplateName(t *template.Template, opts Options, site siteGenerator, schemaType int, name string) string {
+	dir := resourceTemplatesDir
+	if schemaType == typeDataSource {
+		dir = dataSourceTemplatesDir
+	}
+	override := dir + "/" + name + ".md" + opts.TemplateExt
+	if t.Lookup(override) != nil {
+		return override
+	}
+	return site.pageTemplate(schemaType) + opts.TemplateExt
+}
+
+// checkTemplateIncludes returns an error for the first template, by name,
+// that includes a template that does not exist, ie: a partial that is
+// misspelled or missing from the templates directory
+func checkTemplateIncludes(t *template.Template) error {
+	templates := t.Templates()
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name() < templates[j].Name()
+	})
+	for _, tmpl := range templates {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+		for _, include := range templateIncludes(tmpl.Tree.Root) {
+			if t.Lookup(include) == nil {
+				return fmt.Errorf(
+					"Template [%s] includes the template [%s], which does "+
+						"not exist. Partials: [%s].",
+					tmpl.Name(),
+					include,
+					strings.Join(partialTemplateNames(t), ", "),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// templateIncludes returns the names of the templates included with
+// {{template}} actions under the node, in the order they appear
+func templateIncludes(node parse.Node) []string {
+	includes := []string{}
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return includes
+		}
+		for _, child := range n.Nodes {
+			includes = append(includes, templateIncludes(child)...)
+		}
+	case *parse.IfNode:
+		includes = append(includes, branchIncludes(&n.BranchNode)...)
+	case *parse.RangeNode:
+		includes = append(includes, branchIncludes(&n.BranchNode)...)
+	case *parse.WithNode:
+		includes = append(includes, branchIncludes(&n.BranchNode)...)
+	case *parse.TemplateNode:
+		includes = append(includes, n.Name)
+	}
+	return includes
+}
+
+// branchIncludes returns the names of the templates included in either
+// branch of an if, range, or with action
+func branchIncludes(n *parse.BranchNode) []string {
+	includes := templateIncludes(n.List)
+	if n.ElseList != nil {
+		includes = append(includes, templateIncludes(n.ElseList)...)
+	}
+	return includes
+}
+
+// partialTemplateNames returns the sorted names of the partial templates
+func partialTemplateNames(t *template.Template) []string {
+	names := []string{}
+	for _, tmpl := range t.Templates() {
+		if strings.HasPrefix(tmpl.Name(), partialTemplatePrefix) {
+			names = append(names, tmpl.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseBuiltinTemplate parses the body of a built-in template into the
+// template tree under the supplied name with the template extension from the
+// options appended. Built-in templates refer to other templates by their
+// name with the default template extension; these references are rewritten
+// to use the template extension from the options.
+func parseBuiltinTemplate(t *template.Template, name string, body string, opts Options) error {
+	_, parseErr := t.New(name + opts.TemplateExt).Parse(builtinTemplateBody(body, opts))
+	return parseErr
+}
+
+// builtinTemplateBody returns the body of a built-in template with its
+// references to other templates rewritten to use the template extension from
+// the options.
+func builtinTemplateBody(body string, opts Options) string {
+	return strings.Replace(
+		body,
+		defaultTemplateFileExt+`"`,
+		opts.TemplateExt+`"`,
+		-1,
+	)
+}
@@ -1,6 +1,7 @@
 module github.com/wayfair/terraform-provider-utils/v2
 
 require (
+	github.com/hashicorp/hcl/v2 v2.3.0
 	github.com/hashicorp/terraform v0.12.5
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.0.4
 )